@@ -0,0 +1,267 @@
+// Package analysis computes metrics of labyrinths, such as the length of the solution path,
+// dead ends, branching and a composite difficulty rating.
+//
+// The package does not depend on the engine: labyrinths are described by their size
+// and a function telling if a block is a wall. Blocks are identified by image.Point
+// values where X is the column and Y is the row.
+package analysis
+
+import (
+	"image"
+	"math"
+)
+
+// Lab describes a labyrinth to analyze.
+type Lab struct {
+	Rows, Cols int
+
+	// Wall tells if the block at the given row and column is a wall.
+	Wall func(row, col int) bool
+
+	// Start and Exit blocks.
+	Start, Exit image.Point
+}
+
+// Metrics holds the metrics of a labyrinth.
+type Metrics struct {
+	// PassableBlocks is the number of non-wall blocks reachable from Start.
+	PassableBlocks int
+
+	// SolutionLength is the length of the shortest path from Start to Exit in steps.
+	// It is -1 if Exit is not reachable.
+	SolutionLength int
+
+	// Tortuosity is the ratio of SolutionLength and the Manhattan distance of Start and Exit.
+	// 1 means a straight (or staircase) solution, bigger values mean more winding solutions.
+	Tortuosity float64
+
+	// DecisionPoints is the number of junctions on the solution path,
+	// where the player has to choose from multiple ways.
+	DecisionPoints int
+
+	// DeadEnds is the number of dead ends (blocks with a single passable neighbour,
+	// excluding Start and Exit).
+	DeadEnds int
+
+	// MeanDeadEndDepth and MaxDeadEndDepth are the mean and max length of
+	// dead-end corridors in steps (measured from the dead end to the closest junction).
+	MeanDeadEndDepth float64
+	MaxDeadEndDepth  int
+
+	// Junctions is the number of blocks with more than 2 passable neighbours.
+	Junctions int
+
+	// BranchingFactor is the mean number of ways to continue at junctions
+	// (not counting the way we came from).
+	BranchingFactor float64
+
+	// RiverFactor is the mean number of blocks per dead-end branch
+	// (blocks off the solution path divided by the number of dead ends).
+	// High values mean few but long side branches (a "flowing" maze),
+	// low values mean many short dead ends.
+	RiverFactor float64
+
+	// LongestCorridor is the length of the longest straight run of passable blocks.
+	LongestCorridor int
+
+	// Rating is a composite difficulty rating in the range of 0..100,
+	// independent of the size of the labyrinth. See Rate() for details.
+	Rating float64
+}
+
+// neighbours holds the offsets of the neighbours of a block.
+var neighbours = []image.Point{{1, 0}, {-1, 0}, {0, -1}, {0, 1}}
+
+// passable tells if the given block is inside the lab and is not a wall.
+func (l *Lab) passable(p image.Point) bool {
+	return p.X >= 0 && p.Y >= 0 && p.X < l.Cols && p.Y < l.Rows && !l.Wall(p.Y, p.X)
+}
+
+// degree returns the number of passable neighbours of the given block.
+func (l *Lab) degree(p image.Point) (n int) {
+	for _, d := range neighbours {
+		if l.passable(p.Add(d)) {
+			n++
+		}
+	}
+	return
+}
+
+// Distances returns the distances in steps of all blocks from the closest of the given
+// source blocks, indexed first by row, then by column. Unreachable blocks have -1 distance.
+func (l *Lab) Distances(sources ...image.Point) [][]int {
+	dist := make([][]int, l.Rows)
+	for row := range dist {
+		dist[row] = make([]int, l.Cols)
+		for col := range dist[row] {
+			dist[row][col] = -1
+		}
+	}
+
+	queue := make([]image.Point, 0, len(sources))
+	for _, s := range sources {
+		if l.passable(s) && dist[s.Y][s.X] < 0 {
+			dist[s.Y][s.X] = 0
+			queue = append(queue, s)
+		}
+	}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, d := range neighbours {
+			n := p.Add(d)
+			if l.passable(n) && dist[n.Y][n.X] < 0 {
+				dist[n.Y][n.X] = dist[p.Y][p.X] + 1
+				queue = append(queue, n)
+			}
+		}
+	}
+	return dist
+}
+
+// Analyze computes the metrics of the given labyrinth.
+func Analyze(l *Lab) *Metrics {
+	m := &Metrics{SolutionLength: -1}
+
+	fromStart := l.Distances(l.Start)
+	fromExit := l.Distances(l.Exit)
+
+	// Count blocks, dead ends, junctions:
+	var branches int
+	var deadEnds []image.Point
+	for row := 0; row < l.Rows; row++ {
+		for col := 0; col < l.Cols; col++ {
+			p := image.Pt(col, row)
+			if fromStart[row][col] < 0 {
+				continue
+			}
+			m.PassableBlocks++
+			switch deg := l.degree(p); {
+			case deg == 1 && p != l.Start && p != l.Exit:
+				deadEnds = append(deadEnds, p)
+			case deg > 2:
+				m.Junctions++
+				branches += deg - 1
+			}
+		}
+	}
+	m.DeadEnds = len(deadEnds)
+	if m.Junctions > 0 {
+		m.BranchingFactor = float64(branches) / float64(m.Junctions)
+	}
+
+	// Dead end depths: walk from the dead end until reaching a junction.
+	sumDepth := 0
+	for _, de := range deadEnds {
+		depth := 0
+		for prev, p := de, de; ; depth++ {
+			if depth > 0 && l.degree(p) != 2 {
+				break
+			}
+			next := p
+			for _, d := range neighbours {
+				if n := p.Add(d); n != prev && l.passable(n) {
+					next = n
+					break
+				}
+			}
+			if next == p {
+				break // Isolated
+			}
+			prev, p = p, next
+		}
+		sumDepth += depth
+		if depth > m.MaxDeadEndDepth {
+			m.MaxDeadEndDepth = depth
+		}
+	}
+	if m.DeadEnds > 0 {
+		m.MeanDeadEndDepth = float64(sumDepth) / float64(m.DeadEnds)
+	}
+
+	// Solution path:
+	if sl := fromStart[l.Exit.Y][l.Exit.X]; sl >= 0 {
+		m.SolutionLength = sl
+		onSolution := 0
+		for row := 0; row < l.Rows; row++ {
+			for col := 0; col < l.Cols; col++ {
+				ds, de := fromStart[row][col], fromExit[row][col]
+				if ds >= 0 && de >= 0 && ds+de == sl {
+					onSolution++
+					if l.degree(image.Pt(col, row)) > 2 {
+						m.DecisionPoints++
+					}
+				}
+			}
+		}
+		if manhattan := abs(l.Exit.X-l.Start.X) + abs(l.Exit.Y-l.Start.Y); manhattan > 0 {
+			m.Tortuosity = float64(sl) / float64(manhattan)
+		} else {
+			m.Tortuosity = 1
+		}
+		if m.DeadEnds > 0 {
+			m.RiverFactor = float64(m.PassableBlocks-onSolution) / float64(m.DeadEnds)
+		}
+	}
+
+	m.LongestCorridor = l.longestCorridor()
+	m.Rating = Rate(m)
+
+	return m
+}
+
+// longestCorridor returns the length of the longest straight run of passable blocks.
+func (l *Lab) longestCorridor() int {
+	longest := 0
+	for row := 0; row < l.Rows; row++ {
+		run := 0
+		for col := 0; col < l.Cols; col++ {
+			if l.Wall(row, col) {
+				run = 0
+				continue
+			}
+			if run++; run > longest {
+				longest = run
+			}
+		}
+	}
+	for col := 0; col < l.Cols; col++ {
+		run := 0
+		for row := 0; row < l.Rows; row++ {
+			if l.Wall(row, col) {
+				run = 0
+				continue
+			}
+			if run++; run > longest {
+				longest = run
+			}
+		}
+	}
+	return longest
+}
+
+// Rate returns the composite difficulty rating of a labyrinth with the given metrics,
+// in the range of 0..100. It is independent of the size of the labyrinth, and is made of:
+//   - 50%: the ratio of the solution path and all passable blocks (how much of the lab must be traversed),
+//   - 30%: the density of decision points on the solution path,
+//   - 20%: how winding the solution path is (based on Tortuosity).
+//
+// Unsolvable labyrinths are rated 0.
+func Rate(m *Metrics) float64 {
+	if m.SolutionLength <= 0 || m.PassableBlocks == 0 {
+		return 0
+	}
+	coverage := float64(m.SolutionLength+1) / float64(m.PassableBlocks)
+	decisions := math.Min(1, 4*float64(m.DecisionPoints)/float64(m.SolutionLength))
+	winding := 1 - 1/m.Tortuosity
+
+	return 100 * (0.5*coverage + 0.3*decisions + 0.2*winding)
+}
+
+// abs returns the absolute value of i.
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,114 @@
+package analysis
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+// parseLab parses a lab from the given text: '#' is a wall, 'S' is the start, 'E' is the exit.
+func parseLab(text string) *Lab {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	l := &Lab{Rows: len(lines), Cols: len(lines[0])}
+	for row, line := range lines {
+		for col, c := range line {
+			switch c {
+			case 'S':
+				l.Start = image.Pt(col, row)
+			case 'E':
+				l.Exit = image.Pt(col, row)
+			}
+		}
+	}
+	l.Wall = func(row, col int) bool { return lines[row][col] == '#' }
+	return l
+}
+
+func TestAnalyze(t *testing.T) {
+	l := parseLab(`
+#######
+#S....#
+#.###.#
+#.#E..#
+#.##.##
+#.....#
+#######`)
+
+	m := Analyze(l)
+	exp := Metrics{
+		PassableBlocks:   18,
+		SolutionLength:   8,
+		Tortuosity:       2,
+		DecisionPoints:   1,
+		DeadEnds:         1,
+		MeanDeadEndDepth: 1,
+		MaxDeadEndDepth:  1,
+		Junctions:        2,
+		BranchingFactor:  2,
+		RiverFactor:      9,
+		LongestCorridor:  5,
+		Rating:           50,
+	}
+	if *m != exp {
+		t.Errorf("Expected %+v, got: %+v", exp, *m)
+	}
+}
+
+func TestAnalyzeUnsolvable(t *testing.T) {
+	l := parseLab(`
+#######
+#S....#
+#.###.#
+#.#E#.#
+#.#####
+#.....#
+#######`)
+
+	m := Analyze(l)
+	if m.SolutionLength != -1 || m.Rating != 0 || m.PassableBlocks != 15 {
+		t.Errorf("Expected unsolvable lab with 15 passable blocks, got: %+v", m)
+	}
+}
+
+func TestDistances(t *testing.T) {
+	l := parseLab(`
+#####
+#S.E#
+#.###
+#####`)
+
+	cases := []struct {
+		sources []image.Point
+		exp     [][]int
+	}{
+		{
+			[]image.Point{l.Start},
+			[][]int{
+				{-1, -1, -1, -1, -1},
+				{-1, 0, 1, 2, -1},
+				{-1, 1, -1, -1, -1},
+				{-1, -1, -1, -1, -1},
+			},
+		},
+		{
+			[]image.Point{l.Start, l.Exit, {0, 0}},
+			[][]int{
+				{-1, -1, -1, -1, -1},
+				{-1, 0, 1, 0, -1},
+				{-1, 1, -1, -1, -1},
+				{-1, -1, -1, -1, -1},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		got := l.Distances(c.sources...)
+		for row := range c.exp {
+			for col := range c.exp[row] {
+				if got[row][col] != c.exp[row][col] {
+					t.Errorf("[sources: %v, %d,%d] Expected %d, got: %d", c.sources, row, col, c.exp[row][col], got[row][col])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"image"
+
+	"github.com/icza/golab/engine"
+)
+
+// AvoidBot is a bot which moves Gopher along the shortest path to the exit
+// that avoids the Bulldogs. It replans when a Bulldog gets close to the planned route.
+// If there is no safe route, it waits, or flees if a Bulldog is close.
+type AvoidBot struct {
+	// counter is the game counter of the planned path
+	counter int
+
+	// path is the planned path (in blocks) Gopher is moving along
+	path []image.Point
+
+	// danger is a reused set of dangerous blocks
+	danger map[image.Point]bool
+}
+
+// NewAvoidBot returns a new AvoidBot.
+func NewAvoidBot() *AvoidBot {
+	return &AvoidBot{danger: map[image.Point]bool{}}
+}
+
+// dangerDist is the distance in blocks around Bulldogs that is considered dangerous.
+const dangerDist = 1
+
+// Control implements engine.Controller.
+func (b *AvoidBot) Control(m *engine.Model) (clicks []engine.Click, keys []engine.Key) {
+	if b.counter != m.Counter {
+		b.counter = m.Counter
+		b.path = nil
+	}
+
+	b.updateDanger(m)
+
+	gb := gopherBlock(m)
+	// Drop the already passed part of the path:
+	for i, p := range b.path {
+		if p == gb {
+			b.path = b.path[i:]
+			break
+		}
+	}
+
+	idle := len(m.TargetPoss) == 0 && engine.BlockOf(m.Gopher.TargetPos.X, m.Gopher.TargetPos.Y) == gb
+	if !idle && !b.endangered() {
+		return // Keep going
+	}
+
+	// Replan from Gopher's current block:
+	path := engine.FindPath(m.Lab, gb, m.ExitBlock, func(p image.Point) bool { return b.danger[p] })
+	if path == nil && b.danger[gb] {
+		path = b.flee(m, gb)
+	}
+	if idle && len(path) < 2 {
+		b.path = path
+		return // Waiting: already standing still, nothing to change
+	}
+	if equalPaths(path, b.path) && !idle {
+		return // Same plan, nothing to do
+	}
+	b.path = path
+
+	// Clear current path (right click), then queue the new one:
+	clicks = append(clicks, engine.Click{Right: true})
+	return append(clicks, clicksFor(path, cap(m.TargetPoss))...), nil
+}
+
+// updateDanger updates the set of dangerous blocks: blocks within dangerDist
+// of the current and target blocks of the Bulldogs.
+func (b *AvoidBot) updateDanger(m *engine.Model) {
+	for p := range b.danger {
+		delete(b.danger, p)
+	}
+	for _, bd := range m.Bulldogs {
+		for _, c := range []image.Point{
+			engine.BlockOf(int(bd.Pos.X), int(bd.Pos.Y)),
+			engine.BlockOf(bd.TargetPos.X, bd.TargetPos.Y),
+		} {
+			for dy := -dangerDist; dy <= dangerDist; dy++ {
+				for dx := -dangerDist; dx <= dangerDist; dx++ {
+					b.danger[c.Add(image.Pt(dx, dy))] = true
+				}
+			}
+		}
+	}
+}
+
+// endangered tells if the planned path (after the current block) goes through a dangerous block.
+func (b *AvoidBot) endangered() bool {
+	for i, p := range b.path {
+		if i > 0 && b.danger[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// fleeDepth is the max distance in blocks Gopher considers when fleeing.
+const fleeDepth = 6
+
+// flee returns a path to a nearby block which is the farthest from the closest Bulldog
+// (distances measured along passages). Returns nil if staying is the best option.
+func (b *AvoidBot) flee(m *engine.Model, gb image.Point) []image.Point {
+	var bulldogs []image.Point
+	for _, bd := range m.Bulldogs {
+		bulldogs = append(bulldogs, engine.BlockOf(int(bd.Pos.X), int(bd.Pos.Y)))
+	}
+	bdDist := engine.Distances(m.Lab, bulldogs...)
+	gDist := engine.Distances(m.Lab, gb)
+
+	best, bestSafety := gb, bdDist[gb.Y][gb.X]
+	for row := gb.Y - fleeDepth; row <= gb.Y+fleeDepth; row++ {
+		for col := gb.X - fleeDepth; col <= gb.X+fleeDepth; col++ {
+			if row < 0 || col < 0 || row >= m.Rows || col >= m.Cols {
+				continue
+			}
+			gd, bd := gDist[row][col], bdDist[row][col]
+			// Must be reachable by Gopher sooner than by the Bulldogs:
+			if gd < 0 || gd > fleeDepth || (bd >= 0 && bd <= gd) {
+				continue
+			}
+			if bd < 0 || bd > bestSafety {
+				best, bestSafety = image.Pt(col, row), bd
+				if bd < 0 {
+					bestSafety = m.Rows * m.Cols // Unreachable by Bulldogs
+				}
+			}
+		}
+	}
+
+	if best == gb {
+		return nil
+	}
+	return engine.FindPath(m.Lab, gb, best, nil)
+}
+
+// equalPaths tells if the 2 paths are equal.
+func equalPaths(p1, p2 []image.Point) bool {
+	if len(p1) != len(p2) {
+		return false
+	}
+	for i := range p1 {
+		if p1[i] != p2[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/icza/golab/engine"
+)
+
+func TestAvoidBotWaiting(t *testing.T) {
+	// The Bulldog blocks the only route to the exit, but Gopher is not in danger:
+	lm, err := engine.ParseLabMap(strings.NewReader(`
+#######
+#######
+#S.B.E#
+#######
+#######
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+
+	e := engine.NewEngine(func() {})
+	e.SetPaused(true) // Just start the game, don't step it
+	e.NewGame(engine.GameConfig{
+		Difficulty: engine.Difficulties[engine.DifficultyDefaultIdx],
+		LabSize:    engine.LabSizes[engine.LabSizeDefaultIdx],
+		Speed:      engine.Speeds[engine.SpeedDefaultIdx],
+		Map:        lm,
+	})
+	e.Step()
+
+	b := NewAvoidBot()
+	for i := 0; i < 3; i++ {
+		if clicks, keys := b.Control(e.Model); len(clicks) > 0 || len(keys) > 0 {
+			t.Errorf("Expected no clicks and keys while waiting, got: %v, %v", clicks, keys)
+		}
+	}
+}
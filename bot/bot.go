@@ -0,0 +1,65 @@
+// Package bot contains computer players (bots) which control Gopher
+// by implementing engine.Controller.
+package bot
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/icza/golab/engine"
+)
+
+// Names is a slice of the names of all bots, usable with New.
+var Names = []string{"path", "avoid"}
+
+// New returns a new bot with the given name (case insensitive).
+func New(name string) (engine.Controller, error) {
+	switch strings.ToLower(name) {
+	case "path":
+		return NewPathBot(), nil
+	case "avoid":
+		return NewAvoidBot(), nil
+	}
+	return nil, fmt.Errorf("unknown bot: %q (valid bots: %s)", name, strings.Join(Names, ", "))
+}
+
+// gopherBlock returns the block Gopher is currently in.
+func gopherBlock(m *engine.Model) image.Point {
+	return engine.BlockOf(int(m.Gopher.Pos.X), int(m.Gopher.Pos.Y))
+}
+
+// clicksFor returns the clicks needed to move along the given path.
+// At most max clicks are returned.
+func clicksFor(path []image.Point, max int) []engine.Click {
+	var clicks []engine.Click
+	for _, wp := range engine.Waypoints(path) {
+		if len(clicks) == max {
+			break
+		}
+		pos := engine.BlockCenter(wp)
+		clicks = append(clicks, engine.Click{X: pos.X, Y: pos.Y, Left: true})
+	}
+	return clicks
+}
+
+// PathBot is a bot which moves Gopher along the shortest path to the exit,
+// not caring about the Bulldogs at all.
+type PathBot struct{}
+
+// NewPathBot returns a new PathBot.
+func NewPathBot() *PathBot {
+	return &PathBot{}
+}
+
+// Control implements engine.Controller.
+func (b *PathBot) Control(m *engine.Model) (clicks []engine.Click, keys []engine.Key) {
+	if len(m.TargetPoss) > 0 {
+		return // Still have queued targets
+	}
+
+	// Plan from the block Gopher is moving to, so Gopher doesn't have to stop:
+	from := engine.BlockOf(m.Gopher.TargetPos.X, m.Gopher.TargetPos.Y)
+	path := engine.FindPath(m.Lab, from, m.ExitBlock, nil)
+	return clicksFor(path, cap(m.TargetPoss)), nil
+}
@@ -0,0 +1,139 @@
+// golab-anim renders a recorded game (replay file) or a game played by a bot
+// to an animated GIF or APNG.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/icza/golab/bot"
+	"github.com/icza/golab/config"
+	"github.com/icza/golab/engine"
+	"github.com/icza/golab/render"
+)
+
+var (
+	configFile = flag.String("config", "", "config file to load (default is golab/config.json in the user's config directory)")
+	replayFile = flag.String("replay", "", "replay file to render")
+	botName    = flag.String("bot", "avoid", "bot to play with if no replay is given (path or avoid)")
+	seed       = flag.Int64("seed", 0, "seed of the bot game (0 means random)")
+	difficulty = flag.String("difficulty", "", "difficulty of the bot game (name of a difficulty preset)")
+	labSize    = flag.String("labsize", "", "lab size of the bot game (name of a lab size preset or custom size in the form of WxH, e.g. 41x21)")
+	maxFrames  = flag.Int("maxframes", 12000, "max number of frames of the bot game")
+	outFile    = flag.String("out", "golab.gif", "output file, format is chosen by extension: .gif or .png (APNG)")
+	frameStep  = flag.Int("step", 2, "render every n-th frame of the game")
+	scale      = flag.Float64("scale", 0.5, "scale of the rendered frames")
+	viewport   = flag.String("viewport", "700x700", "size of the Gopher-centred view to crop to in the form of WxH (in unscaled pixels), empty means the whole lab")
+	themePath  = flag.String("theme", "", "sprite theme (directory or zip file with a theme.json manifest) to render with")
+	solution   = flag.Bool("solution", false, "overlay the solution from the start")
+)
+
+func main() {
+	flag.Parse()
+
+	var cfg *config.Config
+	var err error
+	if *configFile != "" {
+		if cfg, err = config.Load(*configFile); err != nil {
+			log.Fatal(err)
+		}
+	} else if cfg, err = config.LoadDefault(); err != nil {
+		log.Printf("Using built-in defaults: %v", err)
+	}
+	cfg.Apply()
+
+	opts := render.AnimOptions{
+		Options:   render.Options{Objects: true, Solution: *solution},
+		FrameStep: *frameStep,
+		Scale:     *scale,
+	}
+	if *themePath != "" {
+		theme, err := render.LoadTheme(*themePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.Sprites = theme.Sprites
+	}
+	if *viewport != "" {
+		if _, err := fmt.Sscanf(*viewport, "%dx%d", &opts.Viewport.X, &opts.Viewport.Y); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid viewport: %q\n", *viewport)
+			flag.Usage()
+			os.Exit(2)
+		}
+	}
+	ext := strings.ToLower(filepath.Ext(*outFile))
+	if ext != ".gif" && ext != ".png" {
+		fmt.Fprintf(os.Stderr, "unknown output format: %q\n", ext)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var replay *engine.Replay
+	if *replayFile != "" {
+		f, err := os.Open(*replayFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		replay, err = engine.DecodeReplay(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("invalid replay file %q: %v", *replayFile, err)
+		}
+	} else {
+		if err := config.SelectDefaults(*difficulty, *labSize, ""); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			flag.Usage()
+			os.Exit(2)
+		}
+		if replay, err = playBot(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			flag.Usage()
+			os.Exit(2)
+		}
+	}
+
+	anim := render.RenderReplay(replay, opts)
+
+	f, err := os.Create(*outFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	if ext == ".gif" {
+		err = anim.EncodeGIF(f)
+	} else {
+		err = anim.EncodeAPNG(f)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Rendered %d frames (seed: %d) to %s\n", anim.Len(), replay.Config.Seed, *outFile)
+}
+
+// playBot plays a game headless with the bot, and returns its replay.
+func playBot() (*engine.Replay, error) {
+	c, err := bot.New(*botName)
+	if err != nil {
+		return nil, err
+	}
+
+	eng := engine.NewEngine(func() {})
+	eng.SetController(c)
+	gameCfg := config.DefaultGameConfig()
+	gameCfg.Seed = *seed
+	eng.NewGame(gameCfg)
+
+	m := eng.Model
+	eng.Step() // Process the commands that started the game
+	for m.Frame < *maxFrames && !m.Won && !m.Dead {
+		eng.Step()
+	}
+
+	return eng.Replay(), nil
+}
@@ -0,0 +1,86 @@
+// golab-bot runs a bot against the engine headless, and prints the outcome of the games.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/icza/golab/bot"
+	"github.com/icza/golab/config"
+	"github.com/icza/golab/engine"
+)
+
+var (
+	configFile = flag.String("config", "", "config file to load (default is golab/config.json in the user's config directory)")
+	botName    = flag.String("bot", "avoid", "bot to play with (path or avoid)")
+	games      = flag.Int("games", 10, "number of games to play")
+	seed       = flag.Int64("seed", 1, "seed of the first game, subsequent games use subsequent seeds")
+	difficulty = flag.String("difficulty", "", "difficulty (name of a difficulty preset)")
+	labSize    = flag.String("labsize", "", "lab size (name of a lab size preset or custom size in the form of WxH, e.g. 41x21)")
+	maxFrames  = flag.Int("maxframes", 12000, "max number of frames to run a game")
+)
+
+func main() {
+	flag.Parse()
+
+	var cfg *config.Config
+	var err error
+	if *configFile != "" {
+		if cfg, err = config.Load(*configFile); err != nil {
+			log.Fatal(err)
+		}
+	} else if cfg, err = config.LoadDefault(); err != nil {
+		log.Printf("Using built-in defaults: %v", err)
+	}
+	cfg.Apply()
+
+	if err := config.SelectDefaults(*difficulty, *labSize, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if _, err := bot.New(*botName); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	gameCfg := config.DefaultGameConfig()
+	fmt.Printf("Bot: %s, difficulty: %s, lab size: %s\n", *botName, gameCfg.Difficulty, gameCfg.LabSize)
+
+	won := 0
+	var wonFrames int
+	for i := 0; i < *games; i++ {
+		c, _ := bot.New(*botName)
+		eng := engine.NewEngine(func() {})
+		eng.SetController(c)
+		gameCfg.Seed = *seed + int64(i)
+		eng.NewGame(gameCfg)
+
+		m := eng.Model
+		eng.Step() // Process the commands that started the game
+		for m.Frame < *maxFrames && !m.Won && !m.Dead {
+			eng.Step()
+		}
+
+		outcome := "timeout"
+		switch {
+		case m.Won:
+			outcome = "won"
+			won++
+			wonFrames += m.Frame
+		case m.Dead:
+			outcome = "dead"
+		}
+		fmt.Printf("Game #%d (seed: %d): %-7s in %v\n", i+1, gameCfg.Seed, outcome, engine.GameTime(m.Frame).Round(time.Millisecond))
+	}
+
+	fmt.Printf("Won %d of %d games (%.1f%%)", won, *games, float64(won)*100/float64(*games))
+	if won > 0 {
+		fmt.Printf(", mean time to exit: %v", engine.GameTime(wonFrames/won).Round(time.Millisecond))
+	}
+	fmt.Println()
+}
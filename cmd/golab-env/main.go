@@ -0,0 +1,62 @@
+// golab-env exposes the reinforcement learning environment of the game
+// over a line based JSON protocol on stdin / stdout.
+//
+// Example session (requests and responses are single lines):
+//
+//	{"cmd": "reset", "seed": 1, "difficulty": "Easy", "labSize": "S"}
+//	{"observation": {"rows": 15, "cols": 15, "channels": 4, "data": [...]}, "reward": 0, "done": false}
+//	{"cmd": "step", "action": 1}
+//	{"observation": {...}, "reward": -0.001, "done": false, "info": {...}}
+//	{"cmd": "close"}
+//
+// Actions: 0: none, 1: right, 2: left, 3: up, 4: down.
+// Observation channels: 0: walls, 1: bulldogs, 2: gopher, 3: exit.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/icza/golab/config"
+	"github.com/icza/golab/env"
+)
+
+var configFile = flag.String("config", "", "config file to load (default is golab/config.json in the user's config directory)")
+
+func main() {
+	flag.Parse()
+
+	var cfg *config.Config
+	var err error
+	if *configFile != "" {
+		if cfg, err = config.Load(*configFile); err != nil {
+			log.Fatal(err)
+		}
+	} else if cfg, err = config.LoadDefault(); err != nil {
+		log.Printf("Using built-in defaults: %v", err)
+	}
+	cfg.Apply()
+
+	out := bufio.NewWriter(os.Stdout)
+	// Flush after each response so the client gets it immediately:
+	err = env.Serve(bufio.NewReader(os.Stdin), flushWriter{out})
+	out.Flush()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// flushWriter is a writer which flushes the underlying buffered writer after each write.
+type flushWriter struct {
+	w *bufio.Writer
+}
+
+func (fw flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	if err == nil {
+		err = fw.w.Flush()
+	}
+	return n, err
+}
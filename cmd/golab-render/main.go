@@ -0,0 +1,115 @@
+// golab-render exports labyrinths to PNG (drawn with the sprites of the game)
+// or to SVG (scalable, printable).
+//
+// The labyrinth is generated from the given seed and presets, or loaded from a lab map file or a lab code.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/icza/golab/config"
+	"github.com/icza/golab/engine"
+	"github.com/icza/golab/render"
+)
+
+var (
+	configFile = flag.String("config", "", "config file to load (default is golab/config.json in the user's config directory)")
+	seed       = flag.Int64("seed", 0, "seed of the game (0 means random)")
+	difficulty = flag.String("difficulty", "", "difficulty (name of a difficulty preset), affects the Bulldogs")
+	labSize    = flag.String("labsize", "", "lab size (name of a lab size preset or custom size in the form of WxH, e.g. 41x21)")
+	placement  = flag.String("placement", "corners", "placement of start and exit: corners, farthest, random or edges")
+	mapFile    = flag.String("map", "", "lab map file (plain text format) to render instead of a generated lab")
+	labCode    = flag.String("lab", "", "lab code to render instead of a generated lab")
+	outFile    = flag.String("out", "lab.png", "output file, format is chosen by extension: .png or .svg")
+	objects    = flag.Bool("objects", false, "draw Gopher, the Bulldogs and path markers too")
+	solution   = flag.Bool("solution", false, "overlay the solution")
+	themePath  = flag.String("theme", "", "sprite theme (directory or zip file with a theme.json manifest) to draw PNG images with")
+	blockMM    = flag.Float64("blockmm", 5, "size of a block in millimeters in SVG output")
+)
+
+func main() {
+	flag.Parse()
+
+	var cfg *config.Config
+	var err error
+	if *configFile != "" {
+		if cfg, err = config.Load(*configFile); err != nil {
+			log.Fatal(err)
+		}
+	} else if cfg, err = config.LoadDefault(); err != nil {
+		log.Printf("Using built-in defaults: %v", err)
+	}
+	cfg.Apply()
+
+	if err := config.SelectDefaults(*difficulty, *labSize, ""); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	ext := strings.ToLower(filepath.Ext(*outFile))
+	if ext != ".png" && ext != ".svg" {
+		fmt.Fprintf(os.Stderr, "unknown output format: %q\n", ext)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	gameCfg := config.DefaultGameConfig()
+	gameCfg.Seed = *seed
+	if gameCfg.Gen.Placement, err = engine.ParsePlacement(*placement); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	switch {
+	case *mapFile != "":
+		f, err := os.Open(*mapFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		gameCfg.Map, err = engine.ParseLabMap(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("invalid lab map file %q: %v", *mapFile, err)
+		}
+	case *labCode != "":
+		if gameCfg.Map, err = engine.ParseLabCode(*labCode); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	eng := engine.NewEngine(func() {})
+	eng.NewGame(gameCfg)
+	eng.Step() // Process the command that started the game
+
+	f, err := os.Create(*outFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	m := eng.Model
+	opts := render.Options{Objects: *objects, Solution: *solution}
+	if *themePath != "" {
+		theme, err := render.LoadTheme(*themePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.Sprites = theme.Sprites
+	}
+	if ext == ".svg" {
+		err = render.SVG(f, m, opts, *blockMM)
+	} else {
+		err = png.Encode(f, render.Image(m, opts))
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Rendered %dx%d lab (seed: %d) to %s\n", m.Cols, m.Rows, m.Seed, *outFile)
+}
@@ -0,0 +1,129 @@
+// golab-sim plays many headless games with a bot for each difficulty x lab size combination,
+// in parallel, and reports statistics (win rate, mean time to exit, death locations,
+// bulldog encounters) in CSV or JSON format. Use it to balance the difficulties.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/icza/golab/bot"
+	"github.com/icza/golab/config"
+	"github.com/icza/golab/engine"
+)
+
+var (
+	configFile   = flag.String("config", "", "config file to load (default is golab/config.json in the user's config directory)")
+	botName      = flag.String("bot", "avoid", "bot policy to play with (path or avoid)")
+	games        = flag.Int("games", 20, "number of games to play per combination")
+	seed         = flag.Int64("seed", 1, "seed of the first game of each combination, subsequent games use subsequent seeds")
+	difficulties = flag.String("difficulties", "", "comma separated list of difficulties to simulate (default is all)")
+	labSizes     = flag.String("labsizes", "", "comma separated list of lab sizes (names or WxH) to simulate (default is all)")
+	workers      = flag.Int("workers", runtime.NumCPU(), "number of games to run in parallel")
+	maxFrames    = flag.Int("maxframes", 12000, "max number of frames to run a game")
+	encDist      = flag.Int("encounter", 2, "distance in blocks (on both axes) within which a Bulldog counts as an encounter")
+	format       = flag.String("format", "csv", "output format: csv or json")
+	outFile      = flag.String("out", "", "output file (default is standard output)")
+)
+
+func main() {
+	flag.Parse()
+
+	var cfg *config.Config
+	var err error
+	if *configFile != "" {
+		if cfg, err = config.Load(*configFile); err != nil {
+			log.Fatal(err)
+		}
+	} else if cfg, err = config.LoadDefault(); err != nil {
+		log.Printf("Using built-in defaults: %v", err)
+	}
+	cfg.Apply()
+
+	combos, err := parseCombos()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if _, err := bot.New(*botName); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format != "csv" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format: %q\n", *format)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *workers < 1 {
+		*workers = 1
+	}
+
+	sim := &simulator{
+		botName:   *botName,
+		maxFrames: *maxFrames,
+		encDist:   *encDist,
+	}
+	stats := sim.run(combos, *games, *seed, *workers)
+
+	var w io.Writer = os.Stdout
+	if *outFile != "" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "json" {
+		err = writeJSON(w, stats)
+	} else {
+		err = writeCSV(w, stats)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseCombos returns the difficulty x lab size combinations to simulate, based on the flags.
+func parseCombos() ([]combo, error) {
+	diffs := engine.Difficulties
+	if *difficulties != "" {
+		diffs = nil
+		for _, name := range strings.Split(*difficulties, ",") {
+			idx, err := config.FindDifficulty(strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, engine.Difficulties[idx])
+		}
+	}
+
+	var sizes []*engine.LabSize
+	if *labSizes != "" {
+		for _, name := range strings.Split(*labSizes, ",") {
+			l, err := config.ParseLabSize(strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			sizes = append(sizes, l)
+		}
+	} else {
+		sizes = engine.LabSizes
+	}
+
+	var combos []combo
+	for _, d := range diffs {
+		for _, l := range sizes {
+			combos = append(combos, combo{d, l})
+		}
+	}
+	return combos, nil
+}
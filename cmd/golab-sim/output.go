@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// writeJSON writes the statistics in JSON format, including the death heatmaps.
+func writeJSON(w io.Writer, stats []*comboStats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(stats)
+}
+
+// writeCSV writes the statistics in CSV format, one row for each combination.
+// Death heatmaps are not included, use the JSON format for those.
+func writeCSV(w io.Writer, stats []*comboStats) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"difficulty", "labSize", "games", "won", "dead", "timeouts", "winRate",
+		"meanTimeToExit", "livesLost", "encounters", "encountersPerGame", "catchRate",
+	})
+
+	ftoa := func(f float64) string { return strconv.FormatFloat(f, 'f', 3, 64) }
+	for _, st := range stats {
+		cw.Write([]string{
+			st.Difficulty, st.LabSize,
+			strconv.Itoa(st.Games), strconv.Itoa(st.Won), strconv.Itoa(st.Dead), strconv.Itoa(st.Timeouts),
+			ftoa(st.WinRate), ftoa(st.MeanTimeToExit), strconv.Itoa(st.LivesLost),
+			strconv.Itoa(st.Encounters), ftoa(st.EncountersPerGame), ftoa(st.CatchRate),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
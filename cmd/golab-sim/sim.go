@@ -0,0 +1,190 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/icza/golab/bot"
+	"github.com/icza/golab/engine"
+)
+
+// combo is a difficulty x lab size combination.
+type combo struct {
+	difficulty *engine.Difficulty
+	labSize    *engine.LabSize
+}
+
+// gameResult is the result of a simulated game.
+type gameResult struct {
+	comboIdx int
+
+	won, dead bool
+	frames    int
+
+	// deaths holds the blocks where Gopher lost a life
+	deaths []struct{ col, row int }
+
+	// encounters is the number of times a Bulldog got close to Gopher
+	encounters int
+}
+
+// comboStats holds the aggregated statistics of a combination.
+type comboStats struct {
+	Difficulty string `json:"difficulty"`
+	LabSize    string `json:"labSize"`
+
+	Games    int `json:"games"`
+	Won      int `json:"won"`
+	Dead     int `json:"dead"`
+	Timeouts int `json:"timeouts"`
+
+	WinRate float64 `json:"winRate"`
+
+	// MeanTimeToExit is the mean game time of won games in seconds.
+	MeanTimeToExit float64 `json:"meanTimeToExit"`
+
+	LivesLost int `json:"livesLost"`
+
+	Encounters        int     `json:"encounters"`
+	EncountersPerGame float64 `json:"encountersPerGame"`
+	// CatchRate is the ratio of lives lost and encounters.
+	CatchRate float64 `json:"catchRate"`
+
+	// DeathHeatmap holds the number of lives lost at each block, indexed first by row, then by column.
+	DeathHeatmap [][]int `json:"deathHeatmap"`
+
+	wonFrames int
+}
+
+// simulator runs the simulations.
+type simulator struct {
+	botName   string
+	maxFrames int
+	encDist   int
+}
+
+// run plays the given number of games for all combinations using the given number of workers,
+// and returns the statistics of the combinations.
+func (s *simulator) run(combos []combo, games int, seed int64, workers int) []*comboStats {
+	type job struct {
+		comboIdx int
+		seed     int64
+	}
+	jobs := make(chan job)
+	results := make(chan *gameResult)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- s.play(j.comboIdx, combos[j.comboIdx], j.seed)
+			}
+		}()
+	}
+
+	go func() {
+		for ci := range combos {
+			for i := 0; i < games; i++ {
+				jobs <- job{ci, seed + int64(i)}
+			}
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	stats := make([]*comboStats, len(combos))
+	for ci, c := range combos {
+		heatmap := make([][]int, c.labSize.Rows())
+		for row := range heatmap {
+			heatmap[row] = make([]int, c.labSize.Cols())
+		}
+		stats[ci] = &comboStats{
+			Difficulty:   c.difficulty.Name,
+			LabSize:      c.labSize.String(),
+			DeathHeatmap: heatmap,
+		}
+	}
+
+	for r := range results {
+		st := stats[r.comboIdx]
+		st.Games++
+		switch {
+		case r.won:
+			st.Won++
+			st.wonFrames += r.frames
+		case r.dead:
+			st.Dead++
+		default:
+			st.Timeouts++
+		}
+		st.LivesLost += len(r.deaths)
+		for _, d := range r.deaths {
+			st.DeathHeatmap[d.row][d.col]++
+		}
+		st.Encounters += r.encounters
+	}
+
+	for _, st := range stats {
+		if st.Games > 0 {
+			st.WinRate = float64(st.Won) / float64(st.Games)
+			st.EncountersPerGame = float64(st.Encounters) / float64(st.Games)
+		}
+		if st.Won > 0 {
+			st.MeanTimeToExit = engine.GameTime(st.wonFrames / st.Won).Seconds()
+		}
+		if st.Encounters > 0 {
+			st.CatchRate = float64(st.LivesLost) / float64(st.Encounters)
+		}
+	}
+
+	return stats
+}
+
+// play plays a game of the given combination with the given seed.
+func (s *simulator) play(comboIdx int, c combo, seed int64) *gameResult {
+	ctrl, _ := bot.New(s.botName) // Bot name is validated in advance
+	eng := engine.NewEngine(func() {})
+	eng.SetController(ctrl)
+	eng.NewGame(engine.GameConfig{
+		Difficulty: c.difficulty,
+		LabSize:    c.labSize,
+		Speed:      engine.Speeds[0], // Irrelevant, game is run as fast as possible
+		Seed:       seed,
+	})
+
+	r := &gameResult{comboIdx: comboIdx}
+
+	m := eng.Model
+	eng.Step() // Process the commands that started the game
+	// Gopher may already be caught in the first frame (at the start block):
+	lives, gpos := c.difficulty.Lives(), m.Gopher.Pos
+	near := make([]bool, len(m.Bulldogs))
+	// We're the only goroutine using the engine, safe to read the model without locking.
+	for {
+		if m.Lives < lives {
+			lives = m.Lives
+			r.deaths = append(r.deaths, struct{ col, row int }{int(gpos.X) / engine.BlockSize, int(gpos.Y) / engine.BlockSize})
+		}
+
+		gb := engine.BlockOf(int(m.Gopher.Pos.X), int(m.Gopher.Pos.Y))
+		for i, bd := range m.Bulldogs {
+			bb := engine.BlockOf(int(bd.Pos.X), int(bd.Pos.Y))
+			isNear := engine.BlockDist(bb, gb) <= s.encDist
+			if isNear && !near[i] {
+				r.encounters++
+			}
+			near[i] = isNear
+		}
+
+		if m.Frame >= s.maxFrames || m.Won || m.Dead {
+			break
+		}
+		gpos = m.Gopher.Pos
+		eng.Step()
+	}
+
+	r.won, r.dead, r.frames = m.Won, m.Dead, m.Frame
+	return r
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/icza/golab/engine"
+)
+
+// newTestCombo returns a combo of a new difficulty with the given parameters and the smallest lab size.
+func newTestCombo(t *testing.T, bulldogDensity, aggressiveness, catchRadius float64, minSpawnDist int) combo {
+	d, err := engine.NewDifficulty("Test", bulldogDensity, 1, aggressiveness, catchRadius, minSpawnDist, 1)
+	if err != nil {
+		t.Fatalf("Failed to create difficulty: %v", err)
+	}
+	return combo{d, engine.LabSizes[0]}
+}
+
+func TestSimulatorNoBulldogs(t *testing.T) {
+	s := &simulator{botName: "path", maxFrames: 10000, encDist: 1}
+	stats := s.run([]combo{newTestCombo(t, 0, 0, 0.75, 4)}, 5, 1, 2)
+	if len(stats) != 1 {
+		t.Fatalf("Expected 1 stats, got: %d", len(stats))
+	}
+	st := stats[0]
+	if st.Games != 5 || st.Won != 5 || st.WinRate != 1 || st.MeanTimeToExit <= 0 {
+		t.Errorf("Expected all games won, got: %+v", st)
+	}
+	if st.LivesLost != 0 || st.Encounters != 0 || st.CatchRate != 0 {
+		t.Errorf("Expected no encounters, got: %+v", st)
+	}
+}
+
+func TestSimulatorAggregation(t *testing.T) {
+	s := &simulator{botName: "path", maxFrames: 2000, encDist: 2}
+	combos := []combo{newTestCombo(t, 0, 0, 0.75, 4), newTestCombo(t, 100, 1, 2, 2)}
+	stats := s.run(combos, 10, 1, 4)
+
+	for i, st := range stats {
+		if st.Games != 10 || st.Won+st.Dead+st.Timeouts != st.Games {
+			t.Errorf("[%d] Invalid game counts: %+v", i, st)
+		}
+		if math.Abs(st.WinRate-float64(st.Won)/float64(st.Games)) > 1e-9 {
+			t.Errorf("[%d] Invalid win rate: %+v", i, st)
+		}
+		deaths := 0
+		for _, row := range st.DeathHeatmap {
+			for _, n := range row {
+				deaths += n
+			}
+		}
+		if deaths != st.LivesLost {
+			t.Errorf("[%d] Expected %d deaths in heatmap, got: %d", i, st.LivesLost, deaths)
+		}
+		if math.Abs(st.EncountersPerGame-float64(st.Encounters)/float64(st.Games)) > 1e-9 {
+			t.Errorf("[%d] Invalid encounters per game: %+v", i, st)
+		}
+		if st.Encounters > 0 && math.Abs(st.CatchRate-float64(st.LivesLost)/float64(st.Encounters)) > 1e-9 {
+			t.Errorf("[%d] Invalid catch rate: %+v", i, st)
+		}
+	}
+	if st := stats[1]; st.Dead == 0 || st.LivesLost < st.Dead {
+		t.Errorf("Expected deaths, got: %+v", st)
+	}
+}
+
+func TestSimulatorFirstFrameCatch(t *testing.T) {
+	// Bulldogs may spawn next to Gopher and catch it in the very first frame:
+	s := &simulator{botName: "path", maxFrames: 2000, encDist: 2}
+	st := s.run([]combo{newTestCombo(t, 100, 1, 2, 0)}, 10, 1, 1)[0]
+	if st.Dead == 0 || st.LivesLost != st.Dead {
+		t.Errorf("Expected a life lost in each lost game, got: %+v", st)
+	}
+	if st.CatchRate > 1 {
+		t.Errorf("Expected catch rate <= 1, got: %+v", st)
+	}
+}
+
+func TestSimulatorTimeout(t *testing.T) {
+	s := &simulator{botName: "path", maxFrames: 1, encDist: 1}
+	st := s.run([]combo{newTestCombo(t, 0, 0, 0.75, 4)}, 3, 1, 1)[0]
+	if st.Timeouts != 3 || st.Won != 0 || st.MeanTimeToExit != 0 {
+		t.Errorf("Expected all games timed out, got: %+v", st)
+	}
+}
+
+func TestSimulatorWorkers(t *testing.T) {
+	s := &simulator{botName: "avoid", maxFrames: 2000, encDist: 2}
+	combos := []combo{newTestCombo(t, 40, 0.5, 0.75, 2)}
+	stats1 := s.run(combos, 6, 5, 1)
+	stats4 := s.run(combos, 6, 5, 4)
+	if !reflect.DeepEqual(stats1, stats4) {
+		t.Errorf("Expected the same stats regardless of workers, got: %+v, %+v", stats1[0], stats4[0])
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/icza/golab/config"
+)
+
+// keyQuit is the key combo that quits the game (in addition to Ctrl+C).
+var keyQuit = config.KeyCombo{Key: "Q"}
+
+// parseKeys parses the raw terminal input into key combos.
+//
+// Arrow keys arrive as "ESC [ A" (or "ESC O A") sequences, Alt+key as "ESC key",
+// and Ctrl+letter as control characters. Ctrl+C is reported as keyQuit.
+// Other escape sequences (e.g. function keys, or arrows with modifiers like "ESC [ 1 ; 2 A")
+// are consumed as a whole, only their final byte is mapped.
+func parseKeys(in []byte) (kcs []config.KeyCombo) {
+	for len(in) > 0 {
+		var kc config.KeyCombo
+
+		if in[0] == 0x1b && len(in) >= 3 && (in[1] == '[' || in[1] == 'O') {
+			final, size := parseEscSeq(in)
+			switch final {
+			case 'A':
+				kc.Key = config.KeyUp
+			case 'B':
+				kc.Key = config.KeyDown
+			case 'C':
+				kc.Key = config.KeyRight
+			case 'D':
+				kc.Key = config.KeyLeft
+			}
+			in = in[size:]
+			if kc.Key != "" {
+				kcs = append(kcs, kc)
+			}
+			continue
+		}
+
+		if in[0] == 0x1b && len(in) >= 2 {
+			kc.Alt = true
+			in = in[1:]
+		}
+
+		r, size := utf8.DecodeRune(in)
+		in = in[size:]
+		switch {
+		case r == 3: // Ctrl+C
+			return append(kcs, keyQuit)
+		case r == 0x1b:
+			kc.Key = config.KeyEscape
+		case r == '\r':
+			kc.Key = config.KeyEnter
+		case r == '\t':
+			kc.Key = config.KeyTab
+		case r == ' ':
+			kc.Key = config.KeySpace
+		case r == 0x7f:
+			kc.Key = config.KeyBackspace
+		case r >= 1 && r <= 26:
+			kc.Ctrl = true
+			kc.Key = string('A' + r - 1)
+		case unicode.IsUpper(r):
+			kc.Shift = true
+			kc.Key = string(r)
+		default:
+			kc.Key = string(unicode.ToUpper(r))
+		}
+		kcs = append(kcs, kc)
+	}
+	return
+}
+
+// parseEscSeq parses the escape sequence ("ESC [" or "ESC O") at the beginning of in.
+// It returns the final byte of the sequence and the size of the sequence.
+// Parameter bytes (0x30-0x3F) and intermediate bytes (0x20-0x2F) are skipped.
+// If the sequence is incomplete, final is 0 and size is len(in).
+func parseEscSeq(in []byte) (final byte, size int) {
+	i := 2
+	for i < len(in) && in[i] >= 0x30 && in[i] <= 0x3f {
+		i++
+	}
+	for i < len(in) && in[i] >= 0x20 && in[i] <= 0x2f {
+		i++
+	}
+	if i < len(in) && in[i] >= 0x40 && in[i] <= 0x7e {
+		return in[i], i + 1
+	}
+	return 0, len(in)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/icza/golab/config"
+)
+
+func TestParseKeys(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		exp  []config.KeyCombo
+	}{
+		{"arrows", "\x1b[A\x1bOB", []config.KeyCombo{{Key: config.KeyUp}, {Key: config.KeyDown}}},
+		{"F5", "\x1b[15~x", []config.KeyCombo{{Key: "X"}}},
+		{"shift arrow", "\x1b[1;2Ay", []config.KeyCombo{{Key: config.KeyUp}, {Key: "Y"}}},
+		{"home and end", "\x1b[1~\x1b[4~\x1b[H", nil},
+		{"incomplete", "\x1b[1;2", nil},
+		{"alt and ctrl", "\x1bn\x0e", []config.KeyCombo{{Alt: true, Key: "N"}, {Ctrl: true, Key: "N"}}},
+		{"escape", "\x1b", []config.KeyCombo{{Key: config.KeyEscape}}},
+		{"shift letter", "Q", []config.KeyCombo{{Shift: true, Key: "Q"}}},
+		{"ctrl c", "a\x03b", []config.KeyCombo{{Key: "A"}, keyQuit}},
+	}
+	for _, c := range cases {
+		if got := parseKeys([]byte(c.in)); !reflect.DeepEqual(got, c.exp) {
+			t.Errorf("[%s] Expected %v, got: %v", c.name, c.exp, got)
+		}
+	}
+}
+
+func TestStatusCut(t *testing.T) {
+	r, err := newRenderer("ascii", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	out := bufio.NewWriter(buf)
+	r.status(out, 1, 5, "Nehéz játék")
+	out.Flush()
+	if exp := "\x1b[1;1HNehéz" + ansiClearEOL; buf.String() != exp {
+		t.Errorf("Expected %q, got: %q", exp, buf.String())
+	}
+}
@@ -0,0 +1,246 @@
+// golab-term is a terminal frontend of the game.
+//
+// It renders the labyrinth with ANSI colours and Unicode (or ASCII) characters,
+// keeping Gopher in the center of the viewport, so the game can be played
+// over SSH and on machines without OpenGL.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/icza/golab/config"
+	"github.com/icza/golab/engine"
+)
+
+var (
+	configFile = flag.String("config", "", "config file to load (default is golab/config.json in the user's config directory)")
+	seed       = flag.Int64("seed", 0, "seed of the first game (0 means random)")
+	difficulty = flag.String("difficulty", "", "difficulty (name of a difficulty preset)")
+	labSize    = flag.String("labsize", "", "lab size (name of a lab size preset or custom size in the form of WxH, e.g. 41x21)")
+	speed      = flag.String("speed", "", "speed (name of a speed preset)")
+	style      = flag.String("style", "blocks", "rendering style of walls: blocks, box or ascii")
+	noColor    = flag.Bool("nocolor", false, "disable ANSI colours")
+)
+
+func main() {
+	flag.Parse()
+
+	var cfg *config.Config
+	var err error
+	if *configFile != "" {
+		if cfg, err = config.Load(*configFile); err != nil {
+			log.Fatal(err)
+		}
+	} else if cfg, err = config.LoadDefault(); err != nil {
+		log.Printf("Using built-in defaults: %v", err)
+	}
+	cfg.Apply()
+
+	if err := config.SelectDefaults(*difficulty, *labSize, *speed); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	r, err := newRenderer(*style, !*noColor)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	restore, err := makeRaw()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	out.WriteString(ansiHideCursor + ansiClear)
+	out.Flush()
+
+	t := newTerm(r, out, cfg.Keys)
+	t.run()
+
+	out.WriteString(ansiReset + ansiShowCursor + ansiClear + ansiHome)
+	out.Flush()
+	restore()
+}
+
+// term is the terminal frontend: it runs the engine, renders the model and handles user input.
+type term struct {
+	eng *engine.Engine
+	r   *renderer
+	out *bufio.Writer
+
+	// kb is the key bindings, keys maps key combos to the actions they trigger
+	kb   config.KeyBindings
+	keys map[config.KeyCombo]config.Action
+
+	// Selected preset indices
+	diffIdx, labSizeIdx, speedIdx int
+
+	// redraw signals that a new frame should be rendered
+	redraw chan struct{}
+
+	// cols and rows is the cached size of the terminal, updated on resize signals
+	cols, rows int
+}
+
+// newTerm creates a new term.
+func newTerm(r *renderer, out *bufio.Writer, kb config.KeyBindings) *term {
+	t := &term{
+		r:          r,
+		out:        out,
+		kb:         kb,
+		keys:       kb.Lookup(),
+		diffIdx:    engine.DifficultyDefaultIdx,
+		labSizeIdx: engine.LabSizeDefaultIdx,
+		speedIdx:   engine.SpeedDefaultIdx,
+		redraw:     make(chan struct{}, 1),
+	}
+	t.eng = engine.NewEngine(t.invalidate)
+	return t
+}
+
+// invalidate requests a new frame. Never blocks.
+func (t *term) invalidate() {
+	select {
+	case t.redraw <- struct{}{}:
+	default:
+	}
+}
+
+// run runs the game, returns when the user quits.
+func (t *term) run() {
+	cfg := config.DefaultGameConfig()
+	cfg.Seed = *seed
+	t.eng.NewGame(cfg)
+	go t.eng.Loop()
+
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+	t.updateSize()
+
+	inputs := make(chan []byte)
+	go readInput(inputs)
+
+	for {
+		select {
+		case <-t.redraw:
+			t.render()
+		case <-resize:
+			t.updateSize()
+			t.out.WriteString(ansiClear)
+			t.render()
+		case in, ok := <-inputs:
+			if !ok {
+				return
+			}
+			for _, kc := range parseKeys(in) {
+				if kc == keyQuit {
+					return
+				}
+				t.handleKey(kc)
+			}
+		}
+	}
+}
+
+// readInput reads user input from stdin and sends chunks of it to the given channel.
+// The channel is closed if reading fails.
+func readInput(inputs chan<- []byte) {
+	defer close(inputs)
+	buf := make([]byte, 64)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		in := make([]byte, n)
+		copy(in, buf)
+		inputs <- in
+	}
+}
+
+// handleKey performs the action bound to the given key combo, if any.
+func (t *term) handleKey(kc config.KeyCombo) {
+	action, ok := t.keys[kc]
+	if !ok {
+		return
+	}
+
+	sendKey := func(dir engine.Dir) {
+		t.eng.SendKey(engine.Key{DirKeys: map[engine.Dir]bool{dir: true}})
+	}
+	switch action {
+	case config.ActionLeft:
+		sendKey(engine.DirLeft)
+	case config.ActionRight:
+		sendKey(engine.DirRight)
+	case config.ActionUp:
+		sendKey(engine.DirUp)
+	case config.ActionDown:
+		sendKey(engine.DirDown)
+	case config.ActionNewGame:
+		t.eng.NewGame(engine.GameConfig{
+			Difficulty: engine.Difficulties[t.diffIdx],
+			LabSize:    engine.LabSizes[t.labSizeIdx],
+			Speed:      engine.Speeds[t.speedIdx],
+		})
+		t.out.WriteString(ansiClear)
+	case config.ActionDifficulty:
+		t.diffIdx = (t.diffIdx + 1) % len(engine.Difficulties)
+	case config.ActionLabSize:
+		t.labSizeIdx = (t.labSizeIdx + 1) % len(engine.LabSizes)
+	case config.ActionSpeed:
+		t.speedIdx = (t.speedIdx + 1) % len(engine.Speeds)
+	case config.ActionHint:
+		t.eng.RequestHint()
+	}
+	t.invalidate()
+}
+
+// updateSize queries and caches the size of the terminal.
+// Querying the size is expensive (it runs a command), so it's only done on start and on resize signals.
+func (t *term) updateSize() {
+	var err error
+	t.cols, t.rows, err = termSize()
+	if err != nil {
+		t.cols, t.rows = 80, 24
+	}
+}
+
+// render renders the current state of the game.
+func (t *term) render() {
+	cols, rows := t.cols, t.rows
+
+	m := t.eng.Model
+	m.RLock()
+	t.r.render(t.out, m, cols, rows-2)
+	gameStatus := t.r.gameStatus(m)
+	m.RUnlock()
+
+	// Status lines:
+	status := fmt.Sprintf("%s: New game  %s: Difficulty: %s  %s: Lab size: %s  %s: Speed: %s  %s: Hint  Q: Quit",
+		t.keyHint(config.ActionNewGame),
+		t.keyHint(config.ActionDifficulty), engine.Difficulties[t.diffIdx],
+		t.keyHint(config.ActionLabSize), engine.LabSizes[t.labSizeIdx],
+		t.keyHint(config.ActionSpeed), engine.Speeds[t.speedIdx],
+		t.keyHint(config.ActionHint))
+	t.r.status(t.out, rows-1, cols, status)
+	t.r.status(t.out, rows, cols, gameStatus)
+
+	t.out.Flush()
+}
+
+// keyHint returns the first key combo bound to the given action.
+func (t *term) keyHint(action config.Action) string {
+	if combos := t.kb[action]; len(combos) > 0 {
+		return combos[0]
+	}
+	return "-"
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"time"
+
+	"github.com/icza/golab/engine"
+)
+
+// ANSI escape sequences
+const (
+	ansiClear      = "\x1b[2J"
+	ansiHome       = "\x1b[H"
+	ansiHideCursor = "\x1b[?25l"
+	ansiShowCursor = "\x1b[?25h"
+	ansiReset      = "\x1b[0m"
+	ansiClearEOL   = "\x1b[K"
+)
+
+// Colours of the objects
+const (
+	colWall    = "\x1b[38;5;94m"
+	colGopher  = "\x1b[1;96m"
+	colDead    = "\x1b[1;91m"
+	colBulldog = "\x1b[1;31m"
+	colExit    = "\x1b[1;92m"
+	colMarker  = "\x1b[33m"
+	colHint    = "\x1b[32m"
+	colStatus  = "\x1b[7m"
+)
+
+// cellWidth is the number of terminal columns used to display a block of the lab.
+// Terminal cells are about twice as high as wide, so this keeps blocks square-ish.
+const cellWidth = 2
+
+// renderer renders the model to the terminal.
+type renderer struct {
+	style string
+	color bool
+
+	// cells is a reused buffer of the cells of the viewport.
+	cells []string
+}
+
+// newRenderer creates a new renderer.
+// style must be one of "blocks", "box" and "ascii".
+func newRenderer(style string, color bool) (*renderer, error) {
+	switch style {
+	case "blocks", "box", "ascii":
+	default:
+		return nil, fmt.Errorf("unknown style: %q", style)
+	}
+	return &renderer{style: style, color: color}, nil
+}
+
+// boxChars holds box drawing characters indexed by the walls around a wall block:
+// bit 0: up, bit 1: down, bit 2: left, bit 3: right.
+var boxChars = []rune{
+	'■', '│', '│', '│', '─', '┘', '┐', '┤',
+	'─', '└', '┌', '├', '─', '┴', '┬', '┼',
+}
+
+// render renders the viewport of the lab which fits into the given number of terminal columns and rows.
+// The viewport tries to keep Gopher in the center, like the gio view does.
+// The model must be locked for reading.
+func (r *renderer) render(out *bufio.Writer, m *engine.Model, termCols, termRows int) {
+	viewCols, viewRows := termCols/cellWidth, termRows
+	if viewCols > m.Cols {
+		viewCols = m.Cols
+	}
+	if viewRows > m.Rows {
+		viewRows = m.Rows
+	}
+	if viewCols <= 0 || viewRows <= 0 {
+		return
+	}
+
+	// Top left block of the viewport: try to center Gopher in view,
+	// but it needs correction at the edges of the lab (it can't be centered):
+	gCol, gRow := int(m.Gopher.Pos.X)/engine.BlockSize, int(m.Gopher.Pos.Y)/engine.BlockSize
+	col0, row0 := clamp(gCol-viewCols/2, 0, m.Cols-viewCols), clamp(gRow-viewRows/2, 0, m.Rows-viewRows)
+
+	// First the blocks:
+	if cap(r.cells) < viewCols*viewRows {
+		r.cells = make([]string, viewCols*viewRows)
+	}
+	cells := r.cells[:viewCols*viewRows]
+	for row := 0; row < viewRows; row++ {
+		for col := 0; col < viewCols; col++ {
+			cells[row*viewCols+col] = r.block(m, row0+row, col0+col)
+		}
+	}
+
+	// Now objects in the lab:
+	put := func(x, y int, color, s string) {
+		col, row := x/engine.BlockSize-col0, y/engine.BlockSize-row0
+		if col >= 0 && col < viewCols && row >= 0 && row < viewRows {
+			cells[row*viewCols+col] = r.paint(color, s)
+		}
+	}
+	for _, p := range m.Hint {
+		hp := engine.BlockCenter(p)
+		put(hp.X, hp.Y, colHint, r.pick(" ░", " ░", " +"))
+	}
+	exitPos := engine.BlockCenter(m.ExitBlock)
+	put(exitPos.X, exitPos.Y, colExit, r.pick("▐▌", "▐▌", "[]"))
+	tp := m.Gopher.TargetPos
+	put(tp.X, tp.Y, colMarker, r.pick(" •", " •", " ."))
+	for _, tp := range m.TargetPoss {
+		put(tp.X, tp.Y, colMarker, r.pick(" •", " •", " ."))
+	}
+	for _, bd := range m.Bulldogs {
+		put(int(bd.Pos.X), int(bd.Pos.Y), colBulldog, dirStr("B", bd.Dir))
+	}
+	if m.Dead {
+		put(int(m.Gopher.Pos.X), int(m.Gopher.Pos.Y), colDead, "XX")
+	} else {
+		put(int(m.Gopher.Pos.X), int(m.Gopher.Pos.Y), colGopher, dirStr("G", m.Gopher.Dir))
+	}
+
+	out.WriteString(ansiHome)
+	for row := 0; row < termRows; row++ {
+		if row < viewRows {
+			for _, c := range cells[row*viewCols : (row+1)*viewCols] {
+				out.WriteString(c)
+			}
+		}
+		out.WriteString(ansiClearEOL + "\r\n")
+	}
+}
+
+// block returns the string of the block at the given position.
+func (r *renderer) block(m *engine.Model, row, col int) string {
+	if m.Lab[row][col] != engine.BlockWall {
+		return "  "
+	}
+
+	switch r.style {
+	case "ascii":
+		return r.paint(colWall, "##")
+	case "box":
+		isWall := func(row, col int) bool {
+			return row >= 0 && row < m.Rows && col >= 0 && col < m.Cols && m.Lab[row][col] == engine.BlockWall
+		}
+		idx := 0
+		if isWall(row-1, col) {
+			idx |= 1
+		}
+		if isWall(row+1, col) {
+			idx |= 2
+		}
+		if isWall(row, col-1) {
+			idx |= 4
+		}
+		right := isWall(row, col+1)
+		if right {
+			idx |= 8
+		}
+		s := string(boxChars[idx])
+		if right {
+			s += "─"
+		} else {
+			s += " "
+		}
+		return r.paint(colWall, s)
+	}
+	return r.paint(colWall, "██")
+}
+
+// pick returns the string of the current style.
+func (r *renderer) pick(blocks, box, ascii string) string {
+	switch r.style {
+	case "box":
+		return box
+	case "ascii":
+		return ascii
+	}
+	return blocks
+}
+
+// paint returns s painted with the given color if colors are enabled.
+func (r *renderer) paint(color, s string) string {
+	if !r.color {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// status renders a status line to the given (1-based) terminal row.
+func (r *renderer) status(out *bufio.Writer, row, cols int, s string) {
+	if rs := []rune(s); len(rs) > cols {
+		s = string(rs[:cols]) // Cut by runes (not bytes) to not break multi-byte characters
+	}
+	fmt.Fprintf(out, "\x1b[%d;1H", row)
+	out.WriteString(r.paint(colStatus, s))
+	out.WriteString(ansiClearEOL)
+}
+
+// gameStatus returns the status of the current game.
+// The model must be locked for reading.
+func (r *renderer) gameStatus(m *engine.Model) string {
+	s := fmt.Sprintf("Lives: %d  Time: %v  Bulldogs: %d  Hints: %d", m.Lives,
+		engine.GameTime(m.Frame).Truncate(time.Second), len(m.Bulldogs), m.HintsLeft)
+	switch {
+	case m.Won:
+		s += fmt.Sprintf("  -  YOU WON! Score: %d", m.Score)
+	case m.Dead:
+		s += "  -  Gopher is dead :("
+	}
+	return s
+}
+
+// dirStr returns the string of an object facing the given direction.
+func dirStr(name string, dir engine.Dir) string {
+	switch dir {
+	case engine.DirLeft:
+		return "<" + name
+	case engine.DirUp:
+		return name + "^"
+	case engine.DirDown:
+		return name + "v"
+	}
+	return name + ">"
+}
+
+// clamp returns v clamped to the range min..max. If max < min, min is returned.
+func clamp(v, min, max int) int {
+	if v > max {
+		v = max
+	}
+	if v < min {
+		v = min
+	}
+	return v
+}
@@ -0,0 +1,24 @@
+//go:build windows || js
+// +build windows js
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+var errNotSupported = errors.New("terminal mode is not supported on this platform")
+
+// makeRaw puts the terminal into raw mode, and returns a function that restores the original state.
+func makeRaw() (restore func(), err error) {
+	return nil, errNotSupported
+}
+
+// termSize returns the size of the terminal in character cells.
+func termSize() (cols, rows int, err error) {
+	return 0, 0, errNotSupported
+}
+
+// notifyResize relays terminal resize signals to the given channel.
+func notifyResize(c chan<- os.Signal) {}
@@ -0,0 +1,49 @@
+//go:build !windows && !js
+// +build !windows,!js
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// stty runs the stty command with the given arguments on the controlling terminal,
+// and returns its output.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// makeRaw puts the terminal into raw mode, and returns a function that restores the original state.
+func makeRaw() (restore func(), err error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query terminal state (is stdin a terminal?): %v", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("failed to set terminal to raw mode: %v", err)
+	}
+	return func() { stty(state) }, nil
+}
+
+// termSize returns the size of the terminal in character cells.
+func termSize() (cols, rows int, err error) {
+	out, err := stty("size")
+	if err != nil {
+		return 0, 0, err
+	}
+	_, err = fmt.Sscan(out, &rows, &cols)
+	return
+}
+
+// notifyResize relays terminal resize signals to the given channel.
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
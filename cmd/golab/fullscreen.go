@@ -0,0 +1,22 @@
+//go:build !js
+// +build !js
+
+package main
+
+import (
+	"log"
+
+	"gioui.org/app"
+)
+
+// setFullscreen switches the window to fullscreen mode.
+// The gio version in use does not support fullscreen windows on this platform.
+func setFullscreen(w *app.Window) {
+	log.Println("Fullscreen mode is not supported on this platform.")
+}
+
+// fullscreenToggle returns a function that toggles fullscreen mode of the window.
+// Returns nil as it's not supported on this platform.
+func fullscreenToggle(w *app.Window) func() {
+	return nil
+}
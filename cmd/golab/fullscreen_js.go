@@ -0,0 +1,35 @@
+package main
+
+import (
+	"syscall/js"
+
+	"gioui.org/app"
+)
+
+// setFullscreen switches the window to fullscreen mode.
+// Browsers only allow this in response to a user action,
+// so fullscreen is requested on the first click.
+func setFullscreen(w *app.Window) {
+	doc := js.Global().Get("document")
+	var onClick js.Func
+	onClick = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		doc.Get("documentElement").Call("requestFullscreen")
+		doc.Call("removeEventListener", "click", onClick)
+		onClick.Release()
+		return nil
+	})
+	doc.Call("addEventListener", "click", onClick)
+}
+
+// fullscreenToggle returns a function that toggles fullscreen mode of the window.
+// Browsers only allow this in response to a user action (which a key press is).
+func fullscreenToggle(w *app.Window) func() {
+	return func() {
+		doc := js.Global().Get("document")
+		if doc.Get("fullscreenElement").IsNull() {
+			doc.Get("documentElement").Call("requestFullscreen")
+		} else {
+			doc.Call("exitFullscreen")
+		}
+	}
+}
@@ -1,25 +1,284 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
 	"gioui.org/app"
 	"gioui.org/unit"
+	"github.com/icza/golab/bot"
+	"github.com/icza/golab/config"
 	"github.com/icza/golab/engine"
 	"github.com/icza/golab/view"
 )
 
+var (
+	configFile = flag.String("config", "", "config file to load (default is golab/config.json in the user's config directory)")
+	keyPreset  = flag.String("keys", "", "key binding preset: default, wasd or vi (overrides the key bindings of the config file)")
+	seed       = flag.Int64("seed", 0, "seed of the first game (0 means random)")
+	difficulty = flag.String("difficulty", "", "difficulty of the first game (name of a difficulty preset)")
+	labSize    = flag.String("labsize", "", "lab size of the first game (name of a lab size preset or custom size in the form of WxH, e.g. 41x21)")
+	speed      = flag.String("speed", "", "speed of the first game (name of a speed preset)")
+	placement  = flag.String("placement", "corners", "placement of start and exit: corners, farthest, random or edges")
+	minSolRat  = flag.Float64("minsolution", 0, "min length of the solution relative to the longest path in the lab (0..1)")
+	width      = flag.Int("width", view.WindowWidthPx, "window width in pixels")
+	height     = flag.Int("height", view.WindowHeightPx, "window height in pixels")
+	fullscreen = flag.Bool("fullscreen", false, "start in fullscreen mode")
+	title      = flag.Bool("title", true, "show the title screen on start (not shown when playing a replay or letting a bot play)")
+	dpad       = flag.Bool("dpad", false, "show the on-screen D-pad (for touch screens)")
+	themeName  = flag.String("theme", "", "sprite theme: name of a theme in the themes directory, or a theme directory or zip file")
+	themesDir  = flag.String("themes", "", "directory of sprite themes (default is golab/themes in the user's config directory)")
+	mapFile    = flag.String("map", "", "lab map file (plain text format) to play in the first game")
+	labCode    = flag.String("lab", "", "lab code to play in the first game (in the browser the lab URL parameter is also used)")
+	saveMap    = flag.String("savemap", "", "file to save the lab map of the last game to (written on exit)")
+	replayFile = flag.String("replay", "", "replay file to play back")
+	recordFile = flag.String("record", "", "file to record the replay of the last game to (written on exit)")
+	headless   = flag.Bool("headless", false, "run the game without a window and print the outcome")
+	botName    = flag.String("bot", "", "let the given bot play (path or avoid)")
+	maxFrames  = flag.Int("maxframes", 12000, "max number of frames to run in headless mode (if not playing a replay)")
+)
+
 func main() {
+	flag.Parse()
+
+	var cfg *config.Config
+	var err error
+	if *configFile != "" {
+		cfg, err = config.Load(*configFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else if cfg, err = config.LoadDefault(); err != nil {
+		log.Printf("Using built-in defaults: %v", err)
+	}
+	cfg.Apply()
+
+	if *keyPreset != "" {
+		if cfg.Keys, err = config.PresetKeyBindings(*keyPreset); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			flag.Usage()
+			os.Exit(2)
+		}
+	}
+
+	if err := config.SelectDefaults(*difficulty, *labSize, *speed); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var genOpts engine.GenOptions
+	if genOpts.Placement, err = engine.ParsePlacement(*placement); err == nil {
+		genOpts.MinSolutionRatio = *minSolRat
+		err = genOpts.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var labMap *engine.LabMap
+	if *mapFile != "" {
+		if labMap, err = loadLabMap(*mapFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *labCode == "" {
+		*labCode = urlLabCode()
+	}
+	if labMap == nil && *labCode != "" {
+		if labMap, err = engine.ParseLabCode(*labCode); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	themes, themeIdx, err := loadThemes()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var replay *engine.Replay
+	if *replayFile != "" {
+		if replay, err = loadReplay(*replayFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var ctrl engine.Controller
+	if *botName != "" {
+		if ctrl, err = bot.New(*botName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			flag.Usage()
+			os.Exit(2)
+		}
+	}
+
+	if *headless {
+		eng := engine.NewEngine(func() {})
+		if ctrl != nil {
+			eng.SetController(ctrl)
+		}
+		startGame(eng, replay, labMap, genOpts)
+		runHeadless(eng, replay)
+		saveRecording(eng)
+		saveLabMap(eng)
+		return
+	}
+
 	go func() {
 		w := app.NewWindow(
 			app.Title("Gopher's Labyrinth"),
-			app.Size(unit.Px(view.WindowWidthPx), unit.Px(view.WindowHeightPx)),
+			app.Size(unit.Px(float32(*width)), unit.Px(float32(*height))),
 		)
+		if *fullscreen {
+			setFullscreen(w)
+		}
 
 		eng := engine.NewEngine(w.Invalidate)
+		v := view.New(eng, w)
+		if ctrl != nil {
+			v.SetAutopilot(ctrl)
+		}
+		startGame(eng, replay, labMap, genOpts)
 		go eng.Loop()
 
-		v := view.New(eng, w)
+		v.SetThemes(themes, themeIdx)
+		v.SetGenOptions(genOpts)
+		v.SetFullscreenToggle(fullscreenToggle(w))
+		v.SetDPad(*dpad)
+		v.SetKeyBindings(cfg.Keys)
+		v.SetKeyBindingsSaver(saveKeyBindings)
+		// Only scores of games played by the user are recorded:
+		if replay == nil && ctrl == nil {
+			v.SetHighScores(loadHighScores())
+			if *title {
+				v.ShowTitle()
+			}
+		}
 		v.Loop()
+
+		// Window closed
+		saveRecording(eng)
+		saveLabMap(eng)
+		os.Exit(0)
 	}()
 
 	app.Main()
 }
+
+// startGame starts the first game: plays back the given replay if not nil,
+// else starts a new game with the default presets, the seed flag, the given lab map (may be nil)
+// and the given generation options.
+func startGame(eng *engine.Engine, replay *engine.Replay, labMap *engine.LabMap, genOpts engine.GenOptions) {
+	if replay != nil {
+		eng.PlayReplay(replay)
+		return
+	}
+
+	cfg := config.DefaultGameConfig()
+	cfg.Seed = *seed
+	cfg.Gen = genOpts
+	cfg.Map = labMap
+	eng.NewGame(cfg)
+}
+
+// loadReplay loads a replay from the named file.
+func loadReplay(name string) (*engine.Replay, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := engine.DecodeReplay(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replay file %q: %v", name, err)
+	}
+	return r, nil
+}
+
+// loadLabMap loads a lab map from the named file.
+func loadLabMap(name string) (*engine.LabMap, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lm, err := engine.ParseLabMap(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lab map file %q: %v", name, err)
+	}
+	return lm, nil
+}
+
+// saveRecording saves the replay of the last game if requested by the record flag.
+func saveRecording(eng *engine.Engine) {
+	if *recordFile == "" {
+		return
+	}
+
+	f, err := os.Create(*recordFile)
+	if err != nil {
+		log.Printf("Failed to save replay: %v", err)
+		return
+	}
+	defer f.Close()
+
+	if err := eng.Replay().Encode(f); err != nil {
+		log.Printf("Failed to save replay: %v", err)
+	}
+}
+
+// saveLabMap saves the lab map of the last game if requested by the savemap flag.
+func saveLabMap(eng *engine.Engine) {
+	if *saveMap == "" {
+		return
+	}
+
+	f, err := os.Create(*saveMap)
+	if err != nil {
+		log.Printf("Failed to save lab map: %v", err)
+		return
+	}
+	defer f.Close()
+
+	eng.Model.RLock()
+	lm := eng.Model.LabMap()
+	eng.Model.RUnlock()
+
+	if err := lm.Format(f); err != nil {
+		log.Printf("Failed to save lab map: %v", err)
+	}
+}
+
+// saveKeyBindings saves the given key bindings to the config file given by flag,
+// or to the default location.
+func saveKeyBindings(kb config.KeyBindings) error {
+	name := *configFile
+	if name == "" {
+		var err error
+		if name, err = config.DefaultPath(); err != nil {
+			return err
+		}
+	}
+	return config.SaveKeyBindings(name, kb)
+}
+
+// loadHighScores loads the high scores from the default location, and returns them
+// along with a function that saves them there. Errors are logged.
+func loadHighScores() (config.HighScores, func(hs config.HighScores) error) {
+	name, err := config.DefaultHighScoresPath()
+	if err != nil {
+		log.Printf("High scores are not saved: %v", err)
+		return nil, func(config.HighScores) error { return nil }
+	}
+	hs, err := config.LoadHighScores(name)
+	if err != nil {
+		log.Printf("Failed to load high scores: %v", err)
+	}
+	return hs, func(hs config.HighScores) error { return hs.Save(name) }
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/icza/golab/engine"
+)
+
+// runHeadless runs the game without a window as fast as possible, and prints the outcome.
+//
+// The game is run until it's over, or until the frames of the played back replay
+// or the maxframes flag are exhausted.
+func runHeadless(eng *engine.Engine, replay *engine.Replay) {
+	limit := *maxFrames
+	if replay != nil {
+		limit = replay.Frames
+	}
+
+	m := eng.Model
+	eng.Step() // Process the command that started the game
+	for m.Frame < limit && !m.Won && !m.Dead {
+		eng.Step()
+	}
+
+	outcome := "timeout"
+	switch {
+	case m.Won:
+		outcome = "won"
+	case m.Dead:
+		outcome = "dead"
+	}
+
+	fmt.Printf("Outcome: %s\n", outcome)
+	fmt.Printf("Seed: %d\n", m.Seed)
+	fmt.Printf("Lab size: %dx%d\n", m.Cols, m.Rows)
+	fmt.Printf("Bulldogs: %d\n", len(m.Bulldogs))
+	fmt.Printf("Lives left: %d\n", m.Lives)
+	fmt.Printf("Hints used: %d\n", m.HintsUsed)
+	fmt.Printf("Score: %d\n", m.Score)
+	fmt.Printf("Lab code: %s\n", m.LabMap().Code())
+	fmt.Printf("Frames: %d (game time: %v)\n", m.Frame, engine.GameTime(m.Frame).Round(time.Millisecond))
+}
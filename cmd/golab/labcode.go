@@ -0,0 +1,10 @@
+//go:build !js
+// +build !js
+
+package main
+
+// urlLabCode returns the lab code from the page URL.
+// There is no page URL on this platform, so this always returns an empty string.
+func urlLabCode() string {
+	return ""
+}
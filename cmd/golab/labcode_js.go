@@ -0,0 +1,15 @@
+package main
+
+import "syscall/js"
+
+// urlLabCode returns the lab code from the "lab" query parameter of the page URL,
+// e.g. https://example.com/golab/?lab=<code>.
+func urlLabCode() string {
+	search := js.Global().Get("location").Get("search")
+	params := js.Global().Get("URLSearchParams").New(search)
+	code := params.Call("get", "lab")
+	if code.Type() != js.TypeString {
+		return ""
+	}
+	return code.String()
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/icza/golab/config"
+	"github.com/icza/golab/render"
+)
+
+// loadThemes loads the sprite themes: the default theme, the themes of the themes directory,
+// and the theme given by flag if it's not in the themes directory.
+// The index of the theme to start with is also returned.
+func loadThemes() (themes []*render.Theme, selected int, err error) {
+	themes = []*render.Theme{render.DefaultTheme}
+
+	dir := *themesDir
+	if dir == "" {
+		dir, _ = config.DefaultThemesDir()
+	}
+	if dir != "" {
+		loaded, err := render.LoadThemes(dir)
+		switch {
+		case os.IsNotExist(err):
+			// No themes directory, that's OK
+		case err != nil:
+			log.Printf("Failed to load themes: %v", err)
+		}
+		themes = append(themes, loaded...)
+	}
+
+	if *themeName == "" {
+		return themes, 0, nil
+	}
+	for i, t := range themes {
+		if strings.EqualFold(t.Name, *themeName) {
+			return themes, i, nil
+		}
+	}
+	t, err := render.LoadTheme(*themeName)
+	if err != nil {
+		return nil, 0, err
+	}
+	return append(themes, t), len(themes), nil
+}
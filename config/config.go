@@ -0,0 +1,278 @@
+// Package config handles the external configuration of the game:
+// difficulty, lab size and speed presets, default selections and key bindings.
+//
+// The configuration is stored in JSON format. Sections missing from the
+// configuration file are substituted with the built-in defaults.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/icza/golab/engine"
+)
+
+// Config holds the configuration of the game.
+type Config struct {
+	// Difficulties is the list of selectable difficulties.
+	Difficulties []*engine.Difficulty `json:"difficulties,omitempty"`
+
+	// LabSizes is the list of selectable lab sizes.
+	LabSizes []*engine.LabSize `json:"labSizes,omitempty"`
+
+	// Speeds is the list of selectable speeds.
+	Speeds []*engine.Speed `json:"speeds,omitempty"`
+
+	// KeyPreset is the name of the key binding preset, see PresetKeyBindings.
+	// Keys override the bindings of the preset.
+	KeyPreset string `json:"keyPreset,omitempty"`
+
+	// Keys holds the key bindings.
+	Keys KeyBindings `json:"keys,omitempty"`
+}
+
+// Default returns the built-in default configuration.
+func Default() *Config {
+	return &Config{
+		Difficulties: engine.Difficulties,
+		LabSizes:     engine.LabSizes,
+		Speeds:       engine.Speeds,
+		Keys:         DefaultKeyBindings(),
+	}
+}
+
+// DefaultPath returns the default path of the configuration file,
+// which is "golab/config.json" inside the user's config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "golab", "config.json"), nil
+}
+
+// DefaultThemesDir returns the default directory of the sprite themes,
+// which is "golab/themes" inside the user's config directory.
+func DefaultThemesDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "golab", "themes"), nil
+}
+
+// Load loads the configuration from the named file.
+// Sections missing from the file are filled with the built-in defaults.
+// The returned error contains the file name.
+func Load(name string) (*Config, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c, err := Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %q: %v", name, err)
+	}
+	return c, nil
+}
+
+// Decode decodes and validates a configuration from the given reader.
+// Sections missing from the input are filled with the built-in defaults.
+func Decode(r io.Reader) (*Config, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	c := new(Config)
+	if err := dec.Decode(c); err != nil {
+		return nil, err
+	}
+
+	def := Default()
+	if len(c.Difficulties) == 0 {
+		c.Difficulties = def.Difficulties
+	}
+	if len(c.LabSizes) == 0 {
+		c.LabSizes = def.LabSizes
+	}
+	if len(c.Speeds) == 0 {
+		c.Speeds = def.Speeds
+	}
+	baseKeys := def.Keys
+	if c.KeyPreset != "" {
+		var err error
+		if baseKeys, err = PresetKeyBindings(c.KeyPreset); err != nil {
+			return nil, err
+		}
+	}
+	if len(c.Keys) == 0 {
+		c.Keys = baseKeys
+	} else {
+		// Actions not listed keep the bindings of the preset:
+		for action, combos := range baseKeys {
+			if _, ok := c.Keys[action]; !ok {
+				c.Keys[action] = combos
+			}
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Encode writes the configuration to the given writer in indented JSON format.
+func (c *Config) Encode(w io.Writer) error {
+	data, err := json.MarshalIndent(c, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// SaveKeyBindings saves the given key bindings to the named configuration file,
+// creating the file and its directory if needed.
+//
+// Only the key bindings are written: the key preset is removed (the bindings are complete),
+// other content of the file is kept, so sections omitted from the file keep following the built-in defaults.
+func SaveKeyBindings(name string, kb KeyBindings) error {
+	content := map[string]json.RawMessage{}
+	data, err := ioutil.ReadFile(name)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &content); err != nil {
+			return fmt.Errorf("invalid config file %q: %v", name, err)
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	keys, err := json.Marshal(kb)
+	if err != nil {
+		return err
+	}
+	content["keys"] = keys
+	delete(content, "keyPreset")
+
+	if data, err = json.MarshalIndent(content, "", "\t"); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, append(data, '\n'), 0644)
+}
+
+// Validate validates the configuration.
+//
+// Individual presets are validated when they are decoded, this checks
+// the lists: they must not be empty, names must be unique and
+// at most one preset may be marked as default in each list.
+func (c *Config) Validate() error {
+	check := func(what string, count int, name func(i int) string, isDefault func(i int) bool) error {
+		if count == 0 {
+			return fmt.Errorf("no %s defined", what)
+		}
+		names := map[string]bool{}
+		defaults := 0
+		for i := 0; i < count; i++ {
+			n := name(i)
+			if names[n] {
+				return fmt.Errorf("duplicate %s name: %q", what, n)
+			}
+			names[n] = true
+			if isDefault(i) {
+				defaults++
+			}
+		}
+		if defaults > 1 {
+			return fmt.Errorf("multiple %s are marked as default", what)
+		}
+		return nil
+	}
+
+	if err := check("difficulties", len(c.Difficulties),
+		func(i int) string { return c.Difficulties[i].Name },
+		func(i int) bool { return c.Difficulties[i].Default }); err != nil {
+		return err
+	}
+	if err := check("lab sizes", len(c.LabSizes),
+		func(i int) string { return c.LabSizes[i].Name },
+		func(i int) bool { return c.LabSizes[i].Default }); err != nil {
+		return err
+	}
+	if err := check("speeds", len(c.Speeds),
+		func(i int) string { return c.Speeds[i].Name },
+		func(i int) bool { return c.Speeds[i].Default }); err != nil {
+		return err
+	}
+
+	if c.Keys == nil {
+		return errors.New("no key bindings defined")
+	}
+	return c.Keys.Validate()
+}
+
+// Apply installs the presets of the configuration into the engine package:
+// it sets engine.Difficulties, engine.LabSizes, engine.Speeds and their default indices.
+// If no preset is marked as default in a list, the first one is used as default.
+func (c *Config) Apply() {
+	engine.Difficulties = c.Difficulties
+	engine.DifficultyDefaultIdx = 0
+	for i, d := range c.Difficulties {
+		if d.Default {
+			engine.DifficultyDefaultIdx = i
+		}
+	}
+
+	engine.LabSizes = c.LabSizes
+	engine.LabSizeDefaultIdx = 0
+	for i, l := range c.LabSizes {
+		if l.Default {
+			engine.LabSizeDefaultIdx = i
+		}
+	}
+
+	engine.Speeds = c.Speeds
+	engine.SpeedDefaultIdx = 0
+	for i, s := range c.Speeds {
+		if s.Default {
+			engine.SpeedDefaultIdx = i
+		}
+	}
+}
+
+// LoadDefault loads the configuration from the default path if it exists.
+//
+// If the file does not exist, the built-in defaults are returned with a nil error.
+// If the file exists but is invalid, the built-in defaults are returned along with the error.
+func LoadDefault() (*Config, error) {
+	name, err := DefaultPath()
+	if err != nil {
+		return Default(), nil
+	}
+	return LoadOrDefault(name)
+}
+
+// LoadOrDefault loads the configuration from the named file.
+//
+// If the file does not exist, the built-in defaults are returned with a nil error.
+// If the file exists but is invalid, the built-in defaults are returned along with the error.
+func LoadOrDefault(name string) (*Config, error) {
+	c, err := Load(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Default(), nil
+		}
+		return Default(), err
+	}
+	return c, nil
+}
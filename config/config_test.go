@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSaveKeyBindings(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "golab", "config.json")
+
+	// Saving to a non-existing file:
+	kb, err := PresetKeyBindings("wasd")
+	if err != nil {
+		t.Fatalf("Failed to get preset: %v", err)
+	}
+	if err := SaveKeyBindings(name, kb); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	c, err := Load(name)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if !reflect.DeepEqual(c.Keys, kb) {
+		t.Errorf("Expected keys %v, got: %v", kb, c.Keys)
+	}
+
+	// Other content must be kept, the preset removed, presets must not be written:
+	content := `{"keyPreset": "vi", "speeds": [{"name": "Turbo", "loopDelay": "25ms"}]}`
+	if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	if err := SaveKeyBindings(name, kb); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	if len(m) != 2 || m["speeds"] == nil || m["keys"] == nil {
+		t.Errorf("Expected speeds and keys only, got: %v", keys)
+	}
+	if c, err = Load(name); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if len(c.Speeds) != 1 || !reflect.DeepEqual(c.Keys, kb) {
+		t.Errorf("Expected 1 speed and keys %v, got: %v, %v", kb, c.Speeds, c.Keys)
+	}
+}
+
+func TestDecodeUnknownFields(t *testing.T) {
+	cases := []struct {
+		name, input string
+	}{
+		{"top level", `{"difficulty": []}`},
+		{"difficulty", `{"difficulties": [{"name": "Custom", "bulldogSped": 2}]}`},
+		{"lab size", `{"labSizes": [{"name": "Custom", "rows": 9, "columns": 9}]}`},
+		{"speed", `{"speeds": [{"name": "Custom", "loopDelay": "25ms", "fps": 40}]}`},
+	}
+	for _, c := range cases {
+		if _, err := Decode(strings.NewReader(c.input)); err == nil || !strings.Contains(err.Error(), "unknown field") {
+			t.Errorf("[%s] Expected unknown field error, got: %v", c.name, err)
+		}
+	}
+
+	if _, err := Decode(strings.NewReader(`{"difficulties": [{"name": "Custom", "bulldogSpeed": 2}]}`)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// MaxHighScores is the max number of high scores kept.
+const MaxHighScores = 10
+
+// HighScore is the score of a won game.
+type HighScore struct {
+	Score      int    `json:"score"`
+	Difficulty string `json:"difficulty"`
+	LabSize    string `json:"labSize"`
+
+	// Frames is the number of frames it took to win, see engine.GameTime.
+	Frames int `json:"frames"`
+
+	// Hints is the number of hints used.
+	Hints int `json:"hints"`
+
+	Date time.Time `json:"date"`
+}
+
+// HighScores is the list of high scores in descending order of score.
+type HighScores []HighScore
+
+// DefaultHighScoresPath returns the default path of the high scores file,
+// which is "golab/highscores.json" inside the user's config directory.
+func DefaultHighScoresPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "golab", "highscores.json"), nil
+}
+
+// LoadHighScores loads the high scores from the named file.
+// If the file does not exist, an empty list is returned with a nil error.
+func LoadHighScores(name string) (HighScores, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var hs HighScores
+	if err := json.NewDecoder(f).Decode(&hs); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(hs, func(i, j int) bool { return hs[i].Score > hs[j].Score })
+	if len(hs) > MaxHighScores {
+		hs = hs[:MaxHighScores]
+	}
+	return hs, nil
+}
+
+// Save writes the high scores to the named file, creating its directory if needed.
+func (hs HighScores) Save(name string) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(hs); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Add adds the given score to the high scores if it qualifies.
+// It returns the new high scores and the index of the added score, -1 if it did not qualify.
+// Of equal scores the earlier one ranks higher.
+func (hs HighScores) Add(h HighScore) (HighScores, int) {
+	idx := sort.Search(len(hs), func(i int) bool { return hs[i].Score < h.Score })
+	if idx >= MaxHighScores {
+		return hs, -1
+	}
+
+	hs2 := make(HighScores, 0, len(hs)+1)
+	hs2 = append(hs2, hs[:idx]...)
+	hs2 = append(hs2, h)
+	hs2 = append(hs2, hs[idx:]...)
+	if len(hs2) > MaxHighScores {
+		hs2 = hs2[:MaxHighScores]
+	}
+	return hs2, idx
+}
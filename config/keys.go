@@ -0,0 +1,303 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Action is a user action that can be bound to keys.
+type Action string
+
+// Actions that can be bound to keys.
+const (
+	ActionLeft       Action = "left"
+	ActionRight      Action = "right"
+	ActionUp         Action = "up"
+	ActionDown       Action = "down"
+	ActionNewGame    Action = "newGame"
+	ActionDifficulty Action = "difficulty"
+	ActionLabSize    Action = "labSize"
+	ActionSpeed      Action = "speed"
+	ActionTheme      Action = "theme"
+	ActionAutopilot  Action = "autopilot"
+	ActionMetrics    Action = "metrics"
+	ActionExportAnim Action = "exportAnim"
+	ActionHint       Action = "hint"
+	ActionMinimap    Action = "minimap"
+	ActionZoomIn     Action = "zoomIn"
+	ActionZoomOut    Action = "zoomOut"
+	ActionZoomReset  Action = "zoomReset"
+	ActionPanLeft    Action = "panLeft"
+	ActionPanRight   Action = "panRight"
+	ActionPanUp      Action = "panUp"
+	ActionPanDown    Action = "panDown"
+	ActionFollow     Action = "follow"
+	ActionFullscreen Action = "fullscreen"
+	ActionDPad       Action = "dpad"
+	ActionControls   Action = "controls"
+	ActionMenu       Action = "menu"
+)
+
+// Actions is a slice of all, ordered actions.
+var Actions = []Action{
+	ActionLeft, ActionRight, ActionUp, ActionDown,
+	ActionNewGame, ActionDifficulty, ActionLabSize, ActionSpeed, ActionTheme,
+	ActionAutopilot, ActionMetrics, ActionExportAnim, ActionHint, ActionMinimap,
+	ActionZoomIn, ActionZoomOut, ActionZoomReset,
+	ActionPanLeft, ActionPanRight, ActionPanUp, ActionPanDown, ActionFollow,
+	ActionFullscreen, ActionDPad, ActionControls, ActionMenu,
+}
+
+// Names of the keys that are not single characters.
+const (
+	KeyLeft      = "Left"
+	KeyRight     = "Right"
+	KeyUp        = "Up"
+	KeyDown      = "Down"
+	KeyEnter     = "Enter"
+	KeyEscape    = "Escape"
+	KeyTab       = "Tab"
+	KeySpace     = "Space"
+	KeyBackspace = "Backspace"
+	KeyDelete    = "Delete"
+	KeyHome      = "Home"
+	KeyEnd       = "End"
+	KeyPageUp    = "PageUp"
+	KeyPageDown  = "PageDown"
+)
+
+// namedKeys maps the lower case names (and aliases) of the keys that are not single characters
+// to their canonical names. Function keys are named "F1" ... "F12".
+var namedKeys = map[string]string{
+	"left": KeyLeft, "right": KeyRight, "up": KeyUp, "down": KeyDown,
+	"enter": KeyEnter, "return": KeyEnter,
+	"escape": KeyEscape, "esc": KeyEscape,
+	"tab":       KeyTab,
+	"space":     KeySpace,
+	"backspace": KeyBackspace,
+	"delete":    KeyDelete, "del": KeyDelete,
+	"home": KeyHome, "end": KeyEnd,
+	"pageup": KeyPageUp, "pgup": KeyPageUp,
+	"pagedown": KeyPageDown, "pgdn": KeyPageDown,
+}
+
+func init() {
+	for i := 1; i <= 12; i++ {
+		name := fmt.Sprintf("F%d", i)
+		namedKeys[strings.ToLower(name)] = name
+	}
+}
+
+// KeyCombo is a key with optional modifiers, e.g. "Alt+N".
+type KeyCombo struct {
+	Ctrl, Alt, Shift bool
+
+	// Key is the name of the key. Single characters are in upper case,
+	// other keys have the canonical names listed in the Key constants (e.g. KeyLeft),
+	// function keys are named "F1" ... "F12".
+	Key string
+}
+
+// Normalize returns the normalized form of the key combo, the form key combos are compared in.
+//
+// Shift is ignored for single character keys that are not letters (e.g. "+" or "0"):
+// the character itself tells if Shift is needed, and keyboard layouts differ in which characters need Shift
+// (e.g. "+" is typed with Shift on US layouts), so "Shift++" and "+" are the same key combo.
+func (kc KeyCombo) Normalize() KeyCombo {
+	if r, size := utf8.DecodeRuneInString(kc.Key); size == len(kc.Key) && !unicode.IsLetter(r) {
+		kc.Shift = false
+	}
+	return kc
+}
+
+// ParseKeyCombo parses a key combo in the form of "[Ctrl+][Alt+][Shift+]Key".
+// Modifiers may be listed in any order and are case insensitive.
+// Key is a single character, or the name of another key (case insensitive, see the Key constants);
+// the aliases "Return", "Esc", "Del", "PgUp" and "PgDn" are also accepted.
+func ParseKeyCombo(s string) (KeyCombo, error) {
+	var kc KeyCombo
+
+	parts := strings.Split(s, "+")
+	// Key itself may be "+", which results in empty last parts:
+	if strings.HasSuffix(s, "++") || s == "+" {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+	for _, mod := range parts[:len(parts)-1] {
+		switch strings.ToLower(strings.TrimSpace(mod)) {
+		case "ctrl":
+			kc.Ctrl = true
+		case "alt":
+			kc.Alt = true
+		case "shift":
+			kc.Shift = true
+		default:
+			return kc, fmt.Errorf("invalid modifier %q in key combo %q", mod, s)
+		}
+	}
+
+	key := strings.TrimSpace(parts[len(parts)-1])
+	switch {
+	case key == "":
+		return kc, fmt.Errorf("missing key in key combo %q", s)
+	case utf8.RuneCountInString(key) == 1:
+		key = strings.ToUpper(key)
+	default:
+		name, ok := namedKeys[strings.ToLower(key)]
+		if !ok {
+			return kc, fmt.Errorf("unknown key %q in key combo %q", key, s)
+		}
+		key = name
+	}
+	kc.Key = key
+
+	return kc.Normalize(), nil
+}
+
+// String returns the canonical form of the key combo, which is parseable by ParseKeyCombo.
+func (kc KeyCombo) String() string {
+	var sb strings.Builder
+	if kc.Ctrl {
+		sb.WriteString("Ctrl+")
+	}
+	if kc.Alt {
+		sb.WriteString("Alt+")
+	}
+	if kc.Shift {
+		sb.WriteString("Shift+")
+	}
+	sb.WriteString(kc.Key)
+	return sb.String()
+}
+
+// KeyBindings maps actions to the key combos that trigger them.
+type KeyBindings map[Action][]string
+
+// DefaultKeyBindings returns the default key bindings.
+func DefaultKeyBindings() KeyBindings {
+	return KeyBindings{
+		ActionLeft:       {"Left"},
+		ActionRight:      {"Right"},
+		ActionUp:         {"Up"},
+		ActionDown:       {"Down"},
+		ActionNewGame:    {"Alt+N"},
+		ActionDifficulty: {"Alt+D"},
+		ActionLabSize:    {"Alt+L"},
+		ActionSpeed:      {"Alt+S"},
+		ActionTheme:      {"Alt+T"},
+		ActionAutopilot:  {"Alt+A"},
+		ActionMetrics:    {"Alt+M"},
+		ActionExportAnim: {"Alt+G"},
+		ActionHint:       {"Alt+H"},
+		ActionMinimap:    {"Alt+O"},
+		ActionZoomIn:     {"+", "="},
+		ActionZoomOut:    {"-"},
+		ActionZoomReset:  {"0"},
+		ActionPanLeft:    {"Shift+Left"},
+		ActionPanRight:   {"Shift+Right"},
+		ActionPanUp:      {"Shift+Up"},
+		ActionPanDown:    {"Shift+Down"},
+		ActionFollow:     {"Alt+F"},
+		ActionFullscreen: {"F11"},
+		ActionDPad:       {"Alt+P"},
+		ActionControls:   {"F1"},
+		ActionMenu:       {"Escape"},
+	}
+}
+
+// KeyPresets is the list of names of the key binding presets, see PresetKeyBindings.
+var KeyPresets = []string{"default", "wasd", "vi"}
+
+// PresetKeyBindings returns the key bindings of the named preset.
+//
+// Besides the default bindings, the "wasd" preset moves Gopher with the W, A, S and D keys,
+// and the "vi" preset with the H, J, K and L keys (arrow keys work in all presets).
+func PresetKeyBindings(name string) (KeyBindings, error) {
+	kb := DefaultKeyBindings()
+	switch strings.ToLower(name) {
+	case "default":
+	case "wasd":
+		kb[ActionLeft] = []string{"A", "Left"}
+		kb[ActionRight] = []string{"D", "Right"}
+		kb[ActionUp] = []string{"W", "Up"}
+		kb[ActionDown] = []string{"S", "Down"}
+	case "vi":
+		kb[ActionLeft] = []string{"H", "Left"}
+		kb[ActionRight] = []string{"L", "Right"}
+		kb[ActionUp] = []string{"K", "Up"}
+		kb[ActionDown] = []string{"J", "Down"}
+	default:
+		return nil, fmt.Errorf("unknown key preset: %q (valid presets: %s)", name, strings.Join(KeyPresets, ", "))
+	}
+	return kb, nil
+}
+
+// Validate checks that all actions are known, all key combos are valid,
+// and no key combo is bound to multiple actions.
+func (kb KeyBindings) Validate() error {
+	known := map[Action]bool{}
+	for _, a := range Actions {
+		known[a] = true
+	}
+
+	// Iterate in deterministic order to report the same error each time:
+	actions := make([]string, 0, len(kb))
+	for a := range kb {
+		actions = append(actions, string(a))
+	}
+	sort.Strings(actions)
+
+	bound := map[KeyCombo]Action{}
+	for _, a := range actions {
+		if !known[Action(a)] {
+			return fmt.Errorf("unknown action in key bindings: %q", a)
+		}
+		for _, combo := range kb[Action(a)] {
+			kc, err := ParseKeyCombo(combo)
+			if err != nil {
+				return fmt.Errorf("action %q: %v", a, err)
+			}
+			if other, ok := bound[kc]; ok {
+				return fmt.Errorf("key combo %q is bound to both %q and %q", kc, other, a)
+			}
+			bound[kc] = Action(a)
+		}
+	}
+	return nil
+}
+
+// Copy returns a deep copy of the key bindings.
+func (kb KeyBindings) Copy() KeyBindings {
+	kb2 := make(KeyBindings, len(kb))
+	for action, combos := range kb {
+		kb2[action] = append([]string(nil), combos...)
+	}
+	return kb2
+}
+
+// Bind binds the given key combo to the given action, replacing the action's previous bindings.
+// If the key combo is already bound to another action, the bindings are not changed
+// and an error is returned naming the conflicting action.
+func (kb KeyBindings) Bind(action Action, kc KeyCombo) error {
+	if other, ok := kb.Lookup()[kc]; ok && other != action {
+		return fmt.Errorf("%s is already bound to %s", kc, other)
+	}
+	kb[action] = []string{kc.String()}
+	return nil
+}
+
+// Lookup returns a map from canonical key combos to actions.
+// Key bindings must be valid, invalid key combos are skipped.
+func (kb KeyBindings) Lookup() map[KeyCombo]Action {
+	m := map[KeyCombo]Action{}
+	for action, combos := range kb {
+		for _, combo := range combos {
+			if kc, err := ParseKeyCombo(combo); err == nil {
+				m[kc] = action
+			}
+		}
+	}
+	return m
+}
@@ -0,0 +1,54 @@
+package config
+
+import "testing"
+
+func TestParseKeyCombo(t *testing.T) {
+	cases := []struct {
+		s     string
+		exp   KeyCombo
+		valid bool
+	}{
+		{"Alt+N", KeyCombo{Alt: true, Key: "N"}, true},
+		{"ctrl+shift+a", KeyCombo{Ctrl: true, Shift: true, Key: "A"}, true},
+		{"+", KeyCombo{Key: "+"}, true},
+		{"Shift++", KeyCombo{Key: "+"}, true}, // Shift is ignored for non-letter characters
+		{"Alt+Shift+0", KeyCombo{Alt: true, Key: "0"}, true},
+		{"Shift+Left", KeyCombo{Shift: true, Key: "Left"}, true},
+		{"f11", KeyCombo{Key: "F11"}, true},
+		{"Ctrl+Return", KeyCombo{Ctrl: true, Key: KeyEnter}, true},
+		{"pgup", KeyCombo{Key: KeyPageUp}, true},
+		{"Esc", KeyCombo{Key: KeyEscape}, true},
+		{"Shift+Tab", KeyCombo{Shift: true, Key: KeyTab}, true},
+		{"Hmoe", KeyCombo{}, false},
+		{"F13", KeyCombo{}, false},
+		{"Meta+A", KeyCombo{}, false},
+		{"Alt+", KeyCombo{}, false},
+	}
+	for _, c := range cases {
+		kc, err := ParseKeyCombo(c.s)
+		if (err == nil) != c.valid {
+			t.Errorf("[%s] Expected valid: %v, got error: %v", c.s, c.valid, err)
+			continue
+		}
+		if c.valid && kc != c.exp {
+			t.Errorf("[%s] Expected %+v, got: %+v", c.s, c.exp, kc)
+		}
+	}
+}
+
+func TestKeyBindingsValidate(t *testing.T) {
+	kb := DefaultKeyBindings()
+	if err := kb.Validate(); err != nil {
+		t.Errorf("Default key bindings must be valid: %v", err)
+	}
+
+	kb[ActionHint] = []string{"Alt+Hmoe"}
+	if err := kb.Validate(); err == nil {
+		t.Errorf("Expected unknown key error")
+	}
+
+	kb[ActionHint] = []string{"Shift+-"} // Same as "-" of zoomOut
+	if err := kb.Validate(); err == nil {
+		t.Errorf("Expected conflict error")
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/icza/golab/engine"
+)
+
+// FindDifficulty returns the index of the difficulty with the given name
+// (case insensitive) in engine.Difficulties.
+func FindDifficulty(name string) (int, error) {
+	for i, d := range engine.Difficulties {
+		if strings.EqualFold(d.Name, name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown difficulty: %q", name)
+}
+
+// FindLabSize returns the index of the lab size with the given name
+// (case insensitive) in engine.LabSizes.
+func FindLabSize(name string) (int, error) {
+	for i, l := range engine.LabSizes {
+		if strings.EqualFold(l.Name, name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown lab size: %q", name)
+}
+
+// ParseLabSize returns the lab size with the given name (case insensitive) from engine.LabSizes.
+//
+// s may also be a custom size in the form of WxH (e.g. "41x21"), in which case
+// a new lab size named "Custom" of that size is returned. engine.LabSizes is not modified,
+// so ParseLabSize is safe for concurrent use.
+func ParseLabSize(s string) (*engine.LabSize, error) {
+	if idx, err := FindLabSize(s); err == nil {
+		return engine.LabSizes[idx], nil
+	}
+
+	parts := strings.Split(strings.ToLower(s), "x")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("unknown lab size: %q", s)
+	}
+	cols, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("unknown lab size: %q", s)
+	}
+	rows, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("unknown lab size: %q", s)
+	}
+	return engine.NewLabSize("Custom", rows, cols)
+}
+
+// FindSpeed returns the index of the speed with the given name
+// (case insensitive) in engine.Speeds.
+func FindSpeed(name string) (int, error) {
+	for i, s := range engine.Speeds {
+		if strings.EqualFold(s.Name, name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown speed: %q", name)
+}
+
+// SelectDefaults changes the default presets of the engine to the ones
+// with the given names. Empty names leave the respective default unchanged.
+// See FindDifficulty, ParseLabSize and FindSpeed for the accepted values.
+// A custom lab size is appended to engine.LabSizes so it can be selected in the UI.
+//
+// SelectDefaults modifies the presets of the engine, it is to be called once on startup.
+func SelectDefaults(difficulty, labSize, speed string) (err error) {
+	if difficulty != "" {
+		if engine.DifficultyDefaultIdx, err = FindDifficulty(difficulty); err != nil {
+			return
+		}
+	}
+	if labSize != "" {
+		idx, err := FindLabSize(labSize)
+		if err != nil {
+			l, err := ParseLabSize(labSize)
+			if err != nil {
+				return err
+			}
+			engine.LabSizes = append(engine.LabSizes, l)
+			idx = len(engine.LabSizes) - 1
+		}
+		engine.LabSizeDefaultIdx = idx
+	}
+	if speed != "" {
+		if engine.SpeedDefaultIdx, err = FindSpeed(speed); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// DefaultGameConfig returns a game config made of the default presets of the engine.
+func DefaultGameConfig() engine.GameConfig {
+	return engine.GameConfig{
+		Difficulty: engine.Difficulties[engine.DifficultyDefaultIdx],
+		LabSize:    engine.LabSizes[engine.LabSizeDefaultIdx],
+		Speed:      engine.Speeds[engine.SpeedDefaultIdx],
+	}
+}
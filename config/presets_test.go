@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/icza/golab/engine"
+)
+
+func TestParseLabSize(t *testing.T) {
+	count := len(engine.LabSizes)
+
+	cases := []struct {
+		s          string
+		rows, cols int
+		valid      bool
+	}{
+		{"m", 33, 33, true},
+		{"41x21", 21, 41, true},
+		{"41X21", 21, 41, true},
+		{"40x21", 0, 0, false},
+		{"huge", 0, 0, false},
+		{"41x21abc", 0, 0, false},
+		{"41x21x9", 0, 0, false},
+		{"41x", 0, 0, false},
+		{"x21", 0, 0, false},
+		{" 41x21", 0, 0, false},
+	}
+	for _, c := range cases {
+		l, err := ParseLabSize(c.s)
+		if (err == nil) != c.valid {
+			t.Errorf("[%s] Expected valid: %v, got error: %v", c.s, c.valid, err)
+			continue
+		}
+		if c.valid && (l.Rows() != c.rows || l.Cols() != c.cols) {
+			t.Errorf("[%s] Expected %dx%d, got: %dx%d", c.s, c.cols, c.rows, l.Cols(), l.Rows())
+		}
+	}
+
+	if len(engine.LabSizes) != count {
+		t.Errorf("Expected engine.LabSizes unchanged, got: %v", engine.LabSizes)
+	}
+}
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"image"
+
+	"github.com/icza/golab/analysis"
+)
+
+const (
+	// maxGenAttempts is the max number of labyrinths generated for a game
+	// when looking for one satisfying the generation constraints.
+	maxGenAttempts = 20
+
+	// maxGenBlocks is the max total number of blocks of the labyrinths generated for a game.
+	// Generating and analyzing big labyrinths is slow, so they get fewer attempts.
+	maxGenBlocks = 1 << 20
+)
+
+// genAttempts returns the max number of labyrinths generated for a game
+// when looking for one of the given size satisfying the generation constraints.
+func genAttempts(rows, cols int) int {
+	n := maxGenBlocks / (rows * cols)
+	switch {
+	case n < 1:
+		return 1
+	case n > maxGenAttempts:
+		return maxGenAttempts
+	}
+	return n
+}
+
+// analysisLab returns the analysis.Lab of the given lab with the given start and exit blocks.
+func analysisLab(lab [][]Block, start, exit image.Point) *analysis.Lab {
+	return &analysis.Lab{
+		Rows:  len(lab),
+		Cols:  len(lab[0]),
+		Wall:  func(row, col int) bool { return lab[row][col] == BlockWall },
+		Start: start,
+		Exit:  exit,
+	}
+}
+
+// Analyze computes the metrics of the labyrinth of the model.
+// The model must be locked for reading.
+func (m *Model) Analyze() *analysis.Metrics {
+	return analysis.Analyze(analysisLab(m.Lab, m.StartBlock, m.ExitBlock))
+}
+
+// generate generates a new labyrinth of the given size along with start and exit blocks,
+// satisfying the given generation options and rated at least minRating.
+// If no such labyrinth is generated in genAttempts, the highest rated one is returned
+// from those satisfying the generation options. If none does, the last one is returned
+// with start and exit placed to the farthest blocks (see placeStartExit).
+func (e *Engine) generate(rows, cols int, opts GenOptions, minRating float64) (lab [][]Block, start, exit image.Point) {
+	bestRating := -1.0
+
+	attempts := genAttempts(rows, cols)
+	for attempt := 0; attempt < attempts; attempt++ {
+		l := make([][]Block, rows)
+		for row := range l {
+			l[row] = make([]Block, cols)
+		}
+		generateLab(l, e.rnd)
+
+		s, x, ok := e.placeStartExit(l, opts)
+		if !ok {
+			if bestRating < 0 { // Keep it if nothing better yet
+				lab, start, exit = l, s, x
+			}
+			continue
+		}
+
+		if minRating <= 0 {
+			return l, s, x // No need to rate it
+		}
+
+		if rating := analysis.Analyze(analysisLab(l, s, x)).Rating; rating > bestRating {
+			lab, start, exit, bestRating = l, s, x, rating
+		}
+		if bestRating >= minRating {
+			break
+		}
+	}
+
+	return
+}
@@ -2,19 +2,38 @@ package engine
 
 // GameConfig holds config to start a new game.
 type GameConfig struct {
-	Difficulty *Difficulty
-	LabSize    *LabSize
-	Speed      *Speed
+	Difficulty *Difficulty `json:"difficulty"`
+	LabSize    *LabSize    `json:"labSize"`
+	Speed      *Speed      `json:"speed"`
+
+	// Seed of the random source of the game. 0 means to use a random seed.
+	Seed int64 `json:"seed,omitempty"`
+
+	// Gen holds options of the labyrinth generation.
+	Gen GenOptions `json:"gen"`
+
+	// Map is an optional lab map to play instead of a generated labyrinth.
+	// If provided, LabSize and Gen are ignored.
+	Map *LabMap `json:"map,omitempty"`
 }
 
 // Click describes a click event.
 type Click struct {
-	X, Y  int  // Click coordinates in the lab
-	Left  bool // Tells if left button was pressed
-	Right bool // Tells if right button was pressed
+	X     int  `json:"x"`               // Click X coordinate in the lab
+	Y     int  `json:"y"`               // Click Y coordinate in the lab
+	Left  bool `json:"left,omitempty"`  // Tells if left button was pressed
+	Right bool `json:"right,omitempty"` // Tells if right button was pressed
 }
 
 // Key describes a key event.
+//
+// By default a key press moves Gopher one block. If Hold is set, the direction keys
+// are held down: Gopher keeps moving in the held directions until a Key with Released is sent.
 type Key struct {
-	DirKeys map[Dir]bool // Tells if keys for the directions were pressed
+	DirKeys  map[Dir]bool `json:"dirKeys"`            // Tells if keys for the directions were pressed (or released)
+	Hold     bool         `json:"hold,omitempty"`     // Tells if the pressed keys are held down until released
+	Released bool         `json:"released,omitempty"` // Tells if the keys were released
 }
+
+// HintRequest describes a request for a hint: showing the shortest path to the exit.
+type HintRequest struct{}
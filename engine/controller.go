@@ -0,0 +1,43 @@
+package engine
+
+// Controller controls Gopher instead of the user, e.g. a bot.
+type Controller interface {
+	// Control is called in each iteration while the game is not over,
+	// before Gopher and the Bulldogs are stepped. The model is locked for writing,
+	// it must not be modified nor retained.
+	//
+	// The returned clicks and keys are handled (and recorded) as if they came from the user.
+	Control(m *Model) (clicks []Click, keys []Key)
+}
+
+// controllerCmd is the command to set the controller.
+type controllerCmd struct {
+	c Controller
+}
+
+// SetController enqueues a command to set the controller of Gopher.
+// Pass nil to remove the controller.
+// The controller remains in effect for subsequent games until removed.
+func (e *Engine) SetController(c Controller) {
+	e.cmdChan <- &controllerCmd{c}
+}
+
+// control lets the controller control Gopher.
+func (e *Engine) control() {
+	m := e.Model
+	if e.controller == nil || e.playback != nil || m.Dead || m.Won {
+		return
+	}
+
+	clicks, keys := e.controller.Control(m)
+	for i := range clicks {
+		c := clicks[i]
+		e.record(ReplayEvent{Click: &c})
+		e.handleClick(&c)
+	}
+	for i := range keys {
+		k := keys[i]
+		e.record(ReplayEvent{Key: &k})
+		e.handleKey(&k)
+	}
+}
@@ -1,27 +1,173 @@
 package engine
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
 // Difficulty of the game.
 type Difficulty struct {
 	Name string
 
+	// params of the difficulty, validated.
+	params DifficultyParams
+
+	Default bool
+}
+
+// DifficultyParams holds the parameters of a difficulty.
+type DifficultyParams struct {
 	// "Bulldog density", it tells how many Bulldogs to generate for an area of 1,000 blocks.
 	// For example if this is 10.0 and rows*cols = 21*21 = 441, 10.0*441/1000 = 4.41 => 4 Bulldogs will be generated.
-	bulldogDensity float64
+	BulldogDensity float64 `json:"bulldogDensity"`
 
-	Default bool
+	// BulldogSpeed is the moving speed of Bulldogs relative to Gopher's speed.
+	// 1.0 means Bulldogs move as fast as Gopher.
+	BulldogSpeed float64 `json:"bulldogSpeed"`
+
+	// Aggressiveness tells the probability (0..1) of a Bulldog preferring directions
+	// towards Gopher when choosing its next target. 0 means completely random roaming.
+	Aggressiveness float64 `json:"aggressiveness"`
+
+	// CatchRadius is the distance in blocks (on both axes) within which a Bulldog catches Gopher.
+	CatchRadius float64 `json:"catchRadius"`
+
+	// MinSpawnDist is the minimum distance in blocks (on at least one axis) between Gopher and Bulldogs when spawning.
+	MinSpawnDist int `json:"minSpawnDist"`
+
+	// Lives tells how many times Gopher may be caught before the game is lost.
+	Lives int `json:"lives"`
+
+	// MinRating is the minimum difficulty rating (0..100) of generated labyrinths,
+	// see analysis.Rate(). Generated labyrinths rated lower are rejected
+	// (up to a limited number of attempts).
+	MinRating float64 `json:"minRating"`
+
+	// Hints tells how many hints (showing the path to the exit) may be used in a game.
+	Hints int `json:"hints"`
+
+	// HintPenalty is the fraction (0..1) of the score lost for each hint used.
+	HintPenalty float64 `json:"hintPenalty"`
+
+	// MinimapBulldogs tells if Bulldogs are shown on the minimap.
+	MinimapBulldogs bool `json:"minimapBulldogs"`
+}
+
+// BaseDifficultyParams returns the fixed base parameters of difficulties:
+// Bulldogs move as fast as Gopher, catch radius is 0.75, min spawn distance is 4 and Gopher has 1 life.
+// All other parameters are zero (no Bulldogs, no hints, no min rating, Bulldogs not shown on the minimap).
+// These are used for parameters omitted when decoding a difficulty from JSON.
+func BaseDifficultyParams() DifficultyParams {
+	return DifficultyParams{BulldogSpeed: 1, CatchRadius: 0.75, MinSpawnDist: 4, Lives: 1}
+}
+
+// NewDifficulty creates a new Difficulty from the given parameters.
+// See the fields of DifficultyParams for the meaning of the parameters,
+// the rest of the parameters are taken from BaseDifficultyParams().
+// An error is returned if any of the parameters is invalid.
+func NewDifficulty(name string, bulldogDensity, bulldogSpeed, aggressiveness, catchRadius float64,
+	minSpawnDist, lives int) (*Difficulty, error) {
+
+	p := BaseDifficultyParams()
+	p.BulldogDensity, p.BulldogSpeed, p.Aggressiveness, p.CatchRadius = bulldogDensity, bulldogSpeed, aggressiveness, catchRadius
+	p.MinSpawnDist, p.Lives = minSpawnDist, lives
+	return NewDifficultyParams(name, p)
+}
+
+// NewDifficultyParams creates a new Difficulty from the given parameters.
+// An error is returned if any of the parameters is invalid.
+func NewDifficultyParams(name string, p DifficultyParams) (*Difficulty, error) {
+	switch {
+	case name == "":
+		return nil, errors.New("difficulty name must not be empty")
+	case p.BulldogDensity < 0 || p.BulldogDensity > 100:
+		return nil, fmt.Errorf("difficulty %q: bulldog density must be in range 0..100, got %v", name, p.BulldogDensity)
+	case p.BulldogSpeed <= 0 || p.BulldogSpeed > 4:
+		return nil, fmt.Errorf("difficulty %q: bulldog speed must be in range (0..4], got %v", name, p.BulldogSpeed)
+	case p.Aggressiveness < 0 || p.Aggressiveness > 1:
+		return nil, fmt.Errorf("difficulty %q: aggressiveness must be in range 0..1, got %v", name, p.Aggressiveness)
+	case p.CatchRadius <= 0 || p.CatchRadius > 2:
+		return nil, fmt.Errorf("difficulty %q: catch radius must be in range (0..2], got %v", name, p.CatchRadius)
+	case p.MinSpawnDist < 0:
+		return nil, fmt.Errorf("difficulty %q: min spawn distance must not be negative, got %d", name, p.MinSpawnDist)
+	case p.Lives < 1:
+		return nil, fmt.Errorf("difficulty %q: lives must be at least 1, got %d", name, p.Lives)
+	case p.MinRating < 0 || p.MinRating > 100:
+		return nil, fmt.Errorf("difficulty %q: min rating must be in range 0..100, got %v", name, p.MinRating)
+	case p.Hints < 0:
+		return nil, fmt.Errorf("difficulty %q: hints must not be negative, got %d", name, p.Hints)
+	case p.HintPenalty < 0 || p.HintPenalty > 1:
+		return nil, fmt.Errorf("difficulty %q: hint penalty must be in range 0..1, got %v", name, p.HintPenalty)
+	}
+
+	return &Difficulty{Name: name, params: p}, nil
 }
 
 func (d *Difficulty) String() string {
 	return d.Name
 }
 
+// Params returns the parameters of the difficulty.
+func (d *Difficulty) Params() DifficultyParams {
+	return d.params
+}
+
+// Lives returns the number of lives Gopher has in a game of this difficulty.
+func (d *Difficulty) Lives() int {
+	return d.params.Lives
+}
+
+// difficultyJSON is the JSON representation of Difficulty.
+type difficultyJSON struct {
+	Name string `json:"name"`
+	DifficultyParams
+	Default bool `json:"default,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d *Difficulty) MarshalJSON() ([]byte, error) {
+	return json.Marshal(difficultyJSON{Name: d.Name, DifficultyParams: d.params, Default: d.Default})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The decoded values are validated.
+// Omitted parameters are taken from BaseDifficultyParams().
+func (d *Difficulty) UnmarshalJSON(data []byte) error {
+	dj := difficultyJSON{DifficultyParams: BaseDifficultyParams()}
+	if err := unmarshalStrict(data, &dj); err != nil {
+		return err
+	}
+	d2, err := NewDifficultyParams(dj.Name, dj.DifficultyParams)
+	if err != nil {
+		return err
+	}
+	*d = *d2
+	d.Default = dj.Default
+	return nil
+}
+
 // Difficulties is a slice of all, ordered difficulties.
 var Difficulties = []*Difficulty{
-	&Difficulty{Name: "Baby", bulldogDensity: 0},
-	&Difficulty{Name: "Easy", bulldogDensity: 5},
-	&Difficulty{Name: "Normal", bulldogDensity: 10, Default: true},
-	&Difficulty{Name: "Hard", bulldogDensity: 20},
-	&Difficulty{Name: "Brutal", bulldogDensity: 40},
+	&Difficulty{Name: "Baby", params: DifficultyParams{
+		BulldogDensity: 0, BulldogSpeed: 1, Aggressiveness: 0, CatchRadius: 0.75, MinSpawnDist: 4, Lives: 1,
+		Hints: 5, HintPenalty: 0.1, MinimapBulldogs: true,
+	}},
+	&Difficulty{Name: "Easy", params: DifficultyParams{
+		BulldogDensity: 5, BulldogSpeed: 1, Aggressiveness: 0, CatchRadius: 0.75, MinSpawnDist: 4, Lives: 1,
+		Hints: 3, HintPenalty: 0.15, MinimapBulldogs: true,
+	}},
+	&Difficulty{Name: "Normal", Default: true, params: DifficultyParams{
+		BulldogDensity: 10, BulldogSpeed: 1, Aggressiveness: 0, CatchRadius: 0.75, MinSpawnDist: 4, Lives: 1,
+		MinRating: 35, Hints: 2, HintPenalty: 0.25, MinimapBulldogs: true,
+	}},
+	&Difficulty{Name: "Hard", params: DifficultyParams{
+		BulldogDensity: 20, BulldogSpeed: 1, Aggressiveness: 0, CatchRadius: 0.75, MinSpawnDist: 4, Lives: 1,
+		MinRating: 40, Hints: 1, HintPenalty: 0.4,
+	}},
+	&Difficulty{Name: "Brutal", params: DifficultyParams{
+		BulldogDensity: 40, BulldogSpeed: 1, Aggressiveness: 0, CatchRadius: 0.75, MinSpawnDist: 4, Lives: 1,
+		MinRating: 45, Hints: 0, HintPenalty: 0.5,
+	}},
 }
 
 // DifficultyDefaultIdx is the index of the default difficulty in Difficulties.
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewDifficulty(t *testing.T) {
+	d, err := NewDifficulty("Custom", 12, 1.5, 0.5, 1, 3, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	exp := DifficultyParams{BulldogDensity: 12, BulldogSpeed: 1.5, Aggressiveness: 0.5, CatchRadius: 1, MinSpawnDist: 3, Lives: 2}
+	if d.Name != "Custom" || d.Params() != exp || d.Lives() != 2 {
+		t.Errorf("Expected %+v, got: %+v", exp, d.Params())
+	}
+
+	cases := []struct {
+		name string
+		p    func(p *DifficultyParams)
+	}{
+		{"density", func(p *DifficultyParams) { p.BulldogDensity = -1 }},
+		{"speed", func(p *DifficultyParams) { p.BulldogSpeed = 0 }},
+		{"aggressiveness", func(p *DifficultyParams) { p.Aggressiveness = 1.5 }},
+		{"catch radius", func(p *DifficultyParams) { p.CatchRadius = 3 }},
+		{"spawn dist", func(p *DifficultyParams) { p.MinSpawnDist = -1 }},
+		{"lives", func(p *DifficultyParams) { p.Lives = 0 }},
+	}
+	for _, c := range cases {
+		p := BaseDifficultyParams()
+		c.p(&p)
+		if _, err := NewDifficultyParams("Bad", p); err == nil {
+			t.Errorf("[%s] Expected error for %+v", c.name, p)
+		}
+	}
+	if _, err := NewDifficultyParams("", BaseDifficultyParams()); err == nil {
+		t.Errorf("Expected error for empty name")
+	}
+}
+
+func TestDifficultiesPresets(t *testing.T) {
+	// Presets keep the original gameplay, only the Bulldog density differs.
+	base := BaseDifficultyParams()
+	for _, d := range Difficulties {
+		p := d.Params()
+		if p.BulldogSpeed != base.BulldogSpeed || p.Aggressiveness != base.Aggressiveness ||
+			p.CatchRadius != base.CatchRadius || p.MinSpawnDist != base.MinSpawnDist || p.Lives != base.Lives {
+			t.Errorf("[%s] Expected base params, got: %+v", d.Name, p)
+		}
+	}
+	if !Difficulties[DifficultyDefaultIdx].Default {
+		t.Errorf("Default difficulty not found")
+	}
+}
+
+func TestDifficultyUnmarshalJSON(t *testing.T) {
+	// Omitted parameters must not depend on the (mutable) default difficulty:
+	saved := Difficulties[DifficultyDefaultIdx].params
+	defer func() { Difficulties[DifficultyDefaultIdx].params = saved }()
+	Difficulties[DifficultyDefaultIdx].params.BulldogSpeed = 2
+
+	var d Difficulty
+	if err := json.Unmarshal([]byte(`{"name": "Custom", "bulldogDensity": 7}`), &d); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	exp := BaseDifficultyParams()
+	exp.BulldogDensity = 7
+	if d.Name != "Custom" || d.Params() != exp {
+		t.Errorf("Expected %+v, got: %+v", exp, d.Params())
+	}
+
+	data, err := json.Marshal(&d)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	var d2 Difficulty
+	if err := json.Unmarshal(data, &d2); err != nil || d2 != d {
+		t.Errorf("Expected %+v, got: %+v, err: %v", d, d2, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"name": "Bad", "lives": 0}`), &d); err == nil {
+		t.Errorf("Expected error for invalid lives")
+	}
+	if err := json.Unmarshal([]byte(`{"name": "Bad", "bulldogSped": 2}`), &d); err == nil {
+		t.Errorf("Expected error for unknown field")
+	}
+}
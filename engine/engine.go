@@ -10,27 +10,34 @@ import (
 	"log"
 	"math"
 	"math/rand"
+	"sort"
 	"time"
 )
 
 const (
 	// BlockSize is the size of the labyrinth unit in pixels.
 	BlockSize = 40
-)
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
+	// RespawnGrace is the number of frames Gopher can't be caught for after losing a life.
+	RespawnGrace = 40
+)
 
 var (
 	// dt is the delta time between iterations.
 	// We keep this fixed to simulate slower / faster game speeds.
 	dt = (50 * time.Millisecond).Seconds()
 
-	// v is the moving speed of Gopher and the Buddlogs in pixel/sec.
+	// v is the moving speed of Gopher in pixel/sec.
+	// Speed of the Bulldogs is relative to this, see Difficulty.
 	v = 2.0 * BlockSize
 )
 
+// GameTime returns the game time elapsed in the given number of frames.
+// Game time does not depend on the game speed.
+func GameTime(frames int) time.Duration {
+	return time.Duration(float64(frames) * dt * float64(time.Second))
+}
+
 // Engine calculates and controls the game.
 type Engine struct {
 	Model *Model
@@ -46,6 +53,36 @@ type Engine struct {
 
 	// directions is a reused slice of all directions
 	directions []Dir
+
+	// rnd is the source of randomness of the current game, seeded from the game config.
+	rnd *rand.Rand
+
+	// replay is the recording of the current game.
+	replay *Replay
+
+	// playback is the replay being played back, if any.
+	// While playing back a replay, clicks and keys from the user are ignored.
+	playback *Replay
+	// playbackIdx is the index of the next event to play back.
+	playbackIdx int
+
+	// controller controls Gopher if not nil.
+	controller Controller
+
+	// paused tells if the game is paused.
+	paused bool
+
+	// solutionLen is the length of the solution of the current lab in blocks, used for scoring.
+	solutionLen int
+
+	// heldDirs are the held down direction keys, in the order they were pressed.
+	heldDirs []Dir
+	// turn is the buffered turn: the direction of the last pressed key that could not be taken yet.
+	// DirCount if there is no buffered turn.
+	turn Dir
+
+	// graceUntil is the frame until which Gopher can't be caught (after respawning).
+	graceUntil int
 }
 
 // NewEngine returns a new Engine.
@@ -79,6 +116,24 @@ func (e *Engine) NewGame(cfg GameConfig) {
 	e.cmdChan <- &cfg
 }
 
+// PlayReplay enqueues a command to play back the given replay.
+// Playback ends when a new game is started.
+func (e *Engine) PlayReplay(r *Replay) {
+	e.cmdChan <- r
+}
+
+// Replay returns the recording of the current game up to the current frame.
+// The returned replay may be played back with PlayReplay().
+func (e *Engine) Replay() *Replay {
+	e.Model.RLock()
+	defer e.Model.RUnlock()
+
+	r := *e.replay
+	r.Events = append([]ReplayEvent(nil), r.Events...)
+	r.Frames = e.Model.Frame
+	return &r
+}
+
 // SendClick sends a click event from the user.
 func (e *Engine) SendClick(c Click) {
 	e.cmdChan <- &c
@@ -93,20 +148,43 @@ func (e *Engine) SendKey(k Key) {
 // This function returns only if the user closes the app.
 func (e *Engine) Loop() {
 	for {
-		e.Model.Lock()
+		e.Step()
 
-		e.processCmds()
+		e.invalidate()
 
-		if !e.Model.Won {
-			e.stepGopher()
-			e.stepBulldogs()
-		}
+		time.Sleep(e.cfg.Speed.loopDelay)
+	}
+}
+
+// Step processes queued commands and calculates one iteration of the game.
+// Step does not call invalidate, and does not wait between iterations
+// (that is done by Loop), so it can be used to run the game headless
+// as fast as possible.
+func (e *Engine) Step() {
+	m := e.Model
+	m.Lock()
+	defer m.Unlock()
 
-		e.Model.Unlock()
+	e.processCmds()
+	if e.paused {
+		return
+	}
+	e.playbackEvents()
+	e.control()
 
-		e.invalidate()
+	over := m.Won || m.Dead
 
-		time.Sleep(e.cfg.Speed.loopDelay)
+	if !m.Won {
+		e.stepGopher()
+		e.stepBulldogs()
+	}
+
+	if !over {
+		m.Frame++
+	}
+
+	if m.Hint != nil && (m.Won || m.Dead || m.Frame >= m.HintUntil) {
+		m.Hint = nil
 	}
 }
 
@@ -118,11 +196,31 @@ func (e *Engine) processCmds() {
 		case cmd := <-e.cmdChan:
 			switch cmd := cmd.(type) {
 			case *GameConfig:
+				e.playback = nil
 				e.initNewGame(cmd)
+			case *Replay:
+				cfg := cmd.Config
+				e.initNewGame(&cfg)
+				e.playback, e.playbackIdx = cmd, 0
+			case *controllerCmd:
+				e.controller = cmd.c
+			case *pauseCmd:
+				e.paused = cmd.paused
 			case *Click:
-				e.handleClick(cmd)
+				if e.playback == nil {
+					e.record(ReplayEvent{Click: cmd})
+					e.handleClick(cmd)
+				}
 			case *Key:
-				e.handleKey(cmd)
+				if e.playback == nil {
+					e.record(ReplayEvent{Key: cmd})
+					e.handleKey(cmd)
+				}
+			case *HintRequest:
+				if e.playback == nil {
+					e.record(ReplayEvent{Hint: cmd})
+					e.handleHint()
+				}
 			default:
 				log.Printf("Unhandled cmd type: %T", cmd)
 			}
@@ -133,6 +231,36 @@ func (e *Engine) processCmds() {
 	}
 }
 
+// playbackEvents handles the events of the played back replay due in the current frame.
+func (e *Engine) playbackEvents() {
+	if e.playback == nil {
+		return
+	}
+
+	for events := e.playback.Events; e.playbackIdx < len(events); e.playbackIdx++ {
+		ev := events[e.playbackIdx]
+		if ev.Frame > e.Model.Frame {
+			break
+		}
+		e.record(ev)
+		if ev.Click != nil {
+			e.handleClick(ev.Click)
+		}
+		if ev.Key != nil {
+			e.handleKey(ev.Key)
+		}
+		if ev.Hint != nil {
+			e.handleHint()
+		}
+	}
+}
+
+// record records the given event in the replay of the current game, at the current frame.
+func (e *Engine) record(ev ReplayEvent) {
+	ev.Frame = e.Model.Frame
+	e.replay.Events = append(e.replay.Events, ev)
+}
+
 // handleClick handles a Click command
 func (e *Engine) handleClick(c *Click) {
 	m := e.Model
@@ -199,6 +327,11 @@ func (e *Engine) handleClick(c *Click) {
 func (e *Engine) handleKey(k *Key) {
 	m := e.Model
 
+	if k.Released {
+		e.releaseDirs(k.DirKeys)
+		return
+	}
+
 	if m.Dead || m.Won {
 		return
 	}
@@ -209,6 +342,9 @@ func (e *Engine) handleKey(k *Key) {
 		if !k.DirKeys[dir] {
 			continue
 		}
+		if k.Hold {
+			e.holdDir(dir)
+		}
 		Gopher.Dir = dir
 		// If Gopher's target is more than a block away, clear that target:
 		dx, dy := Gopher.TargetPos.X-int(Gopher.Pos.X), Gopher.TargetPos.Y-int(Gopher.Pos.Y)
@@ -219,75 +355,107 @@ func (e *Engine) handleKey(k *Key) {
 		}
 
 		col, row := Gopher.TargetPos.X/BlockSize, Gopher.TargetPos.Y/BlockSize
-
-		var drow, dcol int
-		switch dir {
-		case DirLeft:
-			dcol = -1
-		case DirRight:
-			dcol = 1
-		case DirUp:
-			drow = -1
-		case DirDown:
-			drow = 1
-		}
+		dcol, drow := dirDelta(dir)
 
 		// If current target is in the opposite direction, then
 		// use the new target as the current (overwriting old).
 		// Else just queue the new target.
+		// If the direction is blocked, buffer it as a turn to take when possible.
 		if dx*dcol < 0 || dy*drow < 0 {
 			m.TargetPoss = m.TargetPoss[:0]
 			Gopher.TargetPos.X = (col+dcol)*BlockSize + BlockSize/2
 			Gopher.TargetPos.Y = (row+drow)*BlockSize + BlockSize/2
+			e.turn = DirCount
 		} else if m.Lab[row+drow][col+dcol] == BlockEmpty {
 			m.TargetPoss = m.TargetPoss[:0]
 			m.TargetPoss = append(m.TargetPoss, image.Point{
 				X: (col+dcol)*BlockSize + BlockSize/2,
 				Y: (row+drow)*BlockSize + BlockSize/2},
 			)
+			e.turn = DirCount
 			break
+		} else {
+			e.turn = dir
 		}
 	}
 }
 
 // initNewGame handles a GameConfig command: initializes a new game.
 func (e *Engine) initNewGame(cfg *GameConfig) {
+	// Use a copy so we can fill the seed without modifying the command's config:
+	cfgCopy := *cfg
+	cfg = &cfgCopy
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
 	e.cfg = cfg
+	e.rnd = rand.New(rand.NewSource(cfg.Seed))
+	e.replay = &Replay{Config: *cfg}
 
 	m := e.Model
 
 	m.Counter++
+	m.Seed = cfg.Seed
+	m.Frame = 0
+	m.Difficulty = cfg.Difficulty
 
 	// Init the labyrinth
-	m.Rows, m.Cols = cfg.LabSize.rows, cfg.LabSize.cols
-	m.Lab = make([][]Block, m.Rows)
-	for row := range m.Lab {
-		m.Lab[row] = make([]Block, m.Cols)
+	if lm := cfg.Map; lm != nil {
+		lm = lm.copy()
+		m.Rows, m.Cols = len(lm.Lab), len(lm.Lab[0])
+		m.Lab, m.StartBlock, m.ExitBlock = lm.Lab, lm.Start, lm.Exit
+	} else {
+		m.Rows, m.Cols = cfg.LabSize.rows, cfg.LabSize.cols
+		m.Lab, m.StartBlock, m.ExitBlock = e.generate(m.Rows, m.Cols, cfg.Gen, cfg.Difficulty.params.MinRating)
 	}
-	generateLab(m.Lab)
-
-	m.ExitPos.X, m.ExitPos.Y = (m.Cols-2)*BlockSize+BlockSize/2, (m.Rows-2)*BlockSize+BlockSize/2
 
 	// Init Gopher
-	m.Gopher = new(MovingObj)
-	m.Gopher.Pos.X = BlockSize + BlockSize/2 // Position Gopher to top left corner
-	m.Gopher.Pos.Y = m.Gopher.Pos.X
-	m.Gopher.Dir = DirRight
-	m.Gopher.TargetPos.X = int(m.Gopher.Pos.X)
-	m.Gopher.TargetPos.Y = int(m.Gopher.Pos.Y)
+	m.Lives = cfg.Difficulty.params.Lives
+	m.Gopher = &MovingObj{v: v}
+	e.respawnGopher()
 
 	// Init bulldogs
-	numBulldogs := int(float64(m.Rows*m.Cols) * cfg.Difficulty.bulldogDensity / 1000)
+	numBulldogs := int(float64(m.Rows*m.Cols) * cfg.Difficulty.params.BulldogDensity / 1000)
+	if cfg.Map != nil && len(cfg.Map.Spawns) > 0 {
+		numBulldogs = len(cfg.Map.Spawns)
+	}
 	m.Bulldogs = make([]*MovingObj, numBulldogs)
+	m.SpawnBlocks = make([]image.Point, numBulldogs)
+	gRow, gCol := int(m.Gopher.Pos.Y)/BlockSize, int(m.Gopher.Pos.X)/BlockSize
+	minDist := cfg.Difficulty.params.MinSpawnDist
+	var spawnCands []image.Point
+	if cfg.Map != nil && len(cfg.Map.Spawns) == 0 {
+		spawnCands = spawnCandidates(m.Lab, image.Pt(gCol, gRow), minDist)
+	}
 	for i := range m.Bulldogs {
-		bd := new(MovingObj)
+		bd := &MovingObj{v: v * cfg.Difficulty.params.BulldogSpeed}
 		m.Bulldogs[i] = bd
 
-		// Place bulldog at a random position
-		var row, col = int(m.Gopher.Pos.Y) / BlockSize, int(m.Gopher.Pos.X) / BlockSize
-		// Give some space to Gopher: do not generate Bulldogs too close:
-		for gr, gc := row, col; (row-gr)*(row-gr) <= 16 && (col-gc)*(col-gc) <= 16; row, col = rPassPos(0, m.Rows), rPassPos(0, m.Cols) {
+		// Place bulldog at a random position.
+		// Give some space to Gopher: do not generate Bulldogs too close
+		// (but after some attempts use the farthest block in case the lab is too small for the desired distance):
+		// Spawn blocks of a lab map are used as-is.
+		var row, col int
+		switch {
+		case cfg.Map != nil && len(cfg.Map.Spawns) > 0:
+			row, col = cfg.Map.Spawns[i].Y, cfg.Map.Spawns[i].X
+		case cfg.Map != nil:
+			// Lab maps may have walls anywhere, choose from the free blocks:
+			p := spawnCands[e.rnd.Intn(len(spawnCands))]
+			row, col = p.Y, p.X
+		default:
+			// Blocks at odd positions are always free in generated labs:
+			far := false
+			for attempt := 0; attempt < 1000 && !far; attempt++ {
+				row, col = rPassPos(e.rnd, 0, m.Rows), rPassPos(e.rnd, 0, m.Cols)
+				far = BlockDist(image.Pt(col, row), image.Pt(gCol, gRow)) > minDist
+			}
+			if !far {
+				p := farthestFree(m.Lab, image.Pt(gCol, gRow))
+				row, col = p.Y, p.X
+			}
 		}
+		m.SpawnBlocks[i] = image.Pt(col, row)
 
 		bd.Pos.X = float64(col*BlockSize + BlockSize/2)
 		bd.Pos.Y = float64(row*BlockSize + BlockSize/2)
@@ -295,13 +463,75 @@ func (e *Engine) initNewGame(cfg *GameConfig) {
 		bd.TargetPos.X, bd.TargetPos.Y = int(bd.Pos.X), int(bd.Pos.Y)
 	}
 
+	// Init hints and score
+	m.HintsLeft, m.HintsUsed = cfg.Difficulty.params.Hints, 0
+	m.Hint, m.HintUntil = nil, 0
+	m.Score = 0
+	e.solutionLen = len(FindPath(m.Lab, m.StartBlock, m.ExitBlock, nil)) - 1
+
 	m.Dead = false
 	m.Won = false
+	e.graceUntil = 0
+
+	// Keys held in the previous game are not recorded in the replay of this one:
+	e.heldDirs = e.heldDirs[:0]
+	e.turn = DirCount
 
 	// Throw away queued targets
 	m.TargetPoss = m.TargetPoss[:0]
 }
 
+// spawnCandidates returns the free blocks of the lab where Bulldogs may be spawned:
+// the ones farther than minDist rows or columns from the given block (Gopher's block).
+// If there are no such blocks, all free blocks are returned.
+func spawnCandidates(lab [][]Block, from image.Point, minDist int) []image.Point {
+	var far, all []image.Point
+	for row := range lab {
+		for col, b := range lab[row] {
+			if b == BlockWall {
+				continue
+			}
+			p := image.Pt(col, row)
+			all = append(all, p)
+			if BlockDist(p, from) > minDist {
+				far = append(far, p)
+			}
+		}
+	}
+	if len(far) > 0 {
+		return far
+	}
+	return all
+}
+
+// farthestFree returns the free block of the lab farthest from the given block (Gopher's block),
+// measured in rows or columns like the min spawn distance.
+func farthestFree(lab [][]Block, from image.Point) (far image.Point) {
+	max := -1
+	for row := range lab {
+		for col, b := range lab[row] {
+			if d := BlockDist(image.Pt(col, row), from); b == BlockEmpty && d > max {
+				far, max = image.Pt(col, row), d
+			}
+		}
+	}
+	return
+}
+
+// respawnGopher positions Gopher to its starting position and throws away queued targets.
+func (e *Engine) respawnGopher() {
+	m := e.Model
+
+	startPos := BlockCenter(m.StartBlock)
+	m.Gopher.Pos.X = float64(startPos.X)
+	m.Gopher.Pos.Y = float64(startPos.Y)
+	m.Gopher.Dir = DirRight
+	m.Gopher.TargetPos.X = int(m.Gopher.Pos.X)
+	m.Gopher.TargetPos.Y = int(m.Gopher.Pos.Y)
+
+	m.TargetPoss = m.TargetPoss[:0]
+}
+
 // stepGopher handles moving the Gopher and also handles the multiple target positions of Gopher.
 func (e *Engine) stepGopher() {
 	m := e.Model
@@ -319,6 +549,8 @@ func (e *Engine) stepGopher() {
 			Gopher.TargetPos = m.TargetPoss[0]
 			// and remove it from the targets:
 			m.TargetPoss = m.TargetPoss[:copy(m.TargetPoss, m.TargetPoss[1:])]
+		} else {
+			e.followHeldDirs()
 		}
 	}
 
@@ -326,8 +558,9 @@ func (e *Engine) stepGopher() {
 	Gopher.step()
 
 	// Check if Gopher reached the exit point
-	if int(m.Gopher.Pos.X) == m.ExitPos.X && int(m.Gopher.Pos.Y) == m.ExitPos.Y {
+	if exitPos := BlockCenter(m.ExitBlock); int(m.Gopher.Pos.X) == exitPos.X && int(m.Gopher.Pos.Y) == exitPos.Y {
 		m.Won = true
+		m.Score = e.score()
 	}
 }
 
@@ -340,6 +573,7 @@ func (e *Engine) stepBulldogs() {
 
 	dirs := e.directions
 
+	caught := false
 	for _, bd := range m.Bulldogs {
 		x, y := int(bd.Pos.X), int(bd.Pos.Y)
 
@@ -351,9 +585,26 @@ func (e *Engine) stepBulldogs() {
 
 			// Shuffle the directions slice:
 			for i := len(dirs) - 1; i > 0; i-- { // last is already random, no use switching with itself
-				r := rand.Intn(i + 1)
+				r := e.rnd.Intn(i + 1)
 				dirs[i], dirs[r] = dirs[r], dirs[i]
 			}
+			// Aggressive Bulldogs check directions towards Gopher first:
+			if e.rnd.Float64() < e.cfg.Difficulty.params.Aggressiveness {
+				towards := func(dir Dir) bool {
+					switch dir {
+					case DirLeft:
+						return gpos.X < bd.Pos.X
+					case DirRight:
+						return gpos.X > bd.Pos.X
+					case DirUp:
+						return gpos.Y < bd.Pos.Y
+					}
+					return gpos.Y > bd.Pos.Y
+				}
+				sort.SliceStable(dirs, func(i, j int) bool {
+					return towards(dirs[i]) && !towards(dirs[j])
+				})
+			}
 
 			var drow, dcol int
 			for _, dir := range dirs {
@@ -384,11 +635,22 @@ func (e *Engine) stepBulldogs() {
 
 		bd.step()
 
-		if !m.Dead {
-			// Check if this Bulldog reached Gopher (but only if not just won)
-			if math.Abs(gpos.X-bd.Pos.X) < BlockSize*0.75 && math.Abs(gpos.Y-bd.Pos.Y) < BlockSize*0.75 && !m.Won {
-				m.Dead = true // OK, we just died
+		if !m.Dead && !caught && m.Frame >= e.graceUntil {
+			// Check if this Bulldog reached Gopher (but only if not just won or respawned)
+			catchDist := BlockSize * e.cfg.Difficulty.params.CatchRadius
+			if math.Abs(gpos.X-bd.Pos.X) < catchDist && math.Abs(gpos.Y-bd.Pos.Y) < catchDist && !m.Won {
+				caught = true
 			}
 		}
 	}
+
+	if caught {
+		m.Lives--
+		if m.Lives > 0 {
+			e.respawnGopher() // Lost a life, but we may continue
+			e.graceUntil = m.Frame + RespawnGrace
+		} else {
+			m.Dead = true // OK, we just died
+		}
+	}
 }
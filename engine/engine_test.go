@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpawnOnMap(t *testing.T) {
+	// Even-sized open map without spawn blocks, Bulldogs must still be spawned on free blocks:
+	lm, err := ParseLabMap(strings.NewReader(`
+######
+#S...#
+#....#
+#....#
+#...E#
+######
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+
+	brutal := Difficulties[len(Difficulties)-1]
+	for _, seed := range []int64{1, 10, 11, 13, 14} {
+		e := NewEngine(func() {})
+		e.NewGame(GameConfig{Difficulty: brutal, LabSize: LabSizes[0], Speed: Speeds[SpeedDefaultIdx], Seed: seed, Map: lm})
+		for i := 0; i < 200; i++ {
+			e.Step()
+		}
+
+		m := e.Model
+		if len(m.Bulldogs) == 0 {
+			t.Errorf("[seed: %d] Expected Bulldogs", seed)
+		}
+		for _, p := range m.SpawnBlocks {
+			if m.Lab[p.Y][p.X] == BlockWall {
+				t.Errorf("[seed: %d] Bulldog spawned on wall: %v", seed, p)
+			}
+		}
+	}
+}
+
+func TestGenerateNonSquare(t *testing.T) {
+	for _, size := range []struct{ rows, cols int }{{9, 41}, {41, 9}, {15, 21}} {
+		ls, err := NewLabSize("Test", size.rows, size.cols)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := NewEngine(func() {})
+		e.NewGame(GameConfig{Difficulty: Difficulties[0], LabSize: ls, Speed: Speeds[SpeedDefaultIdx], Seed: 1})
+		e.Step()
+
+		m := e.Model
+		if len(m.Lab) != size.rows || len(m.Lab[0]) != size.cols {
+			t.Errorf("[%dx%d] Expected lab of %d rows and %d cols, got: %d rows, %d cols",
+				size.cols, size.rows, size.rows, size.cols, len(m.Lab), len(m.Lab[0]))
+		}
+		if FindPath(m.Lab, m.StartBlock, m.ExitBlock, nil) == nil {
+			t.Errorf("[%dx%d] Exit is not reachable", size.cols, size.rows)
+		}
+	}
+}
+
+func TestSpawnFarthest(t *testing.T) {
+	// Min spawn distance can't be satisfied, Bulldogs must be spawned on the farthest blocks:
+	d, err := NewDifficulty("Test", 50, 1, 0, 0.75, 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngine(func() {})
+	e.NewGame(GameConfig{Difficulty: d, LabSize: LabSizes[0], Speed: Speeds[SpeedDefaultIdx], Seed: 1})
+	e.Step()
+
+	m := e.Model
+	if len(m.SpawnBlocks) == 0 {
+		t.Errorf("Expected Bulldogs")
+	}
+	maxDist := m.Rows - 3
+	if m.Cols > m.Rows {
+		maxDist = m.Cols - 3
+	}
+	for _, p := range m.SpawnBlocks {
+		if m.Lab[p.Y][p.X] == BlockWall || BlockDist(p, m.StartBlock) != maxDist {
+			t.Errorf("Expected Bulldog on a farthest free block, got: %v", p)
+		}
+	}
+}
+
+func TestRespawnGrace(t *testing.T) {
+	d, err := NewDifficulty("Test", 100, 1, 1, 2, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngine(func() {})
+	e.NewGame(GameConfig{Difficulty: d, LabSize: LabSizes[0], Speed: Speeds[SpeedDefaultIdx], Seed: 1})
+
+	m := e.Model
+	var caughtFrames []int
+	for i, lives := 0, d.Lives(); i < 1000 && !m.Dead; i++ {
+		frame := m.Frame
+		e.Step()
+		if m.Lives < lives {
+			caughtFrames = append(caughtFrames, frame)
+			lives = m.Lives
+		}
+	}
+	if !m.Dead || len(caughtFrames) != 3 {
+		t.Fatalf("Expected Gopher to be caught 3 times, got: %v", caughtFrames)
+	}
+	for i := 1; i < len(caughtFrames); i++ {
+		if caughtFrames[i]-caughtFrames[i-1] < RespawnGrace {
+			t.Errorf("Expected no catch in %d frames after respawn, got catches in frames: %v", RespawnGrace, caughtFrames)
+		}
+	}
+}
@@ -6,7 +6,7 @@ import (
 
 // generateLab generates a new, random labyrinth.
 // lab must have odd number of rows and columns.
-func generateLab(lab [][]Block) {
+func generateLab(lab [][]Block, rnd *rand.Rand) {
 	rows, cols := len(lab), len(lab[0])
 
 	// Create a "frame":
@@ -19,12 +19,12 @@ func generateLab(lab [][]Block) {
 		lab[rows-1][col] = BlockWall
 	}
 
-	genLabArea(lab, 0, 0, rows-1, cols-1)
+	genLabArea(lab, rnd, 0, 0, cols-1, rows-1)
 }
 
 // genLabArea generates a random labyrinth inside the specified area, borders exclusive.
 // This is a recursive implementation, each iteration divides the area into 2 parts.
-func genLabArea(lab [][]Block, x1, y1, x2, y2 int) {
+func genLabArea(lab [][]Block, rnd *rand.Rand, x1, y1, x2, y2 int) {
 	dx, dy := x2-x1, y2-y1
 
 	// Exit condition from the recursion:
@@ -38,7 +38,7 @@ func genLabArea(lab [][]Block, x1, y1, x2, y2 int) {
 		vert = false
 	} else if dx > dy {
 		vert = true
-	} else if rand.Intn(2) == 0 { // Area is square, choose randomly
+	} else if rnd.Intn(2) == 0 { // Area is square, choose randomly
 		vert = true
 	}
 
@@ -48,42 +48,42 @@ func genLabArea(lab [][]Block, x1, y1, x2, y2 int) {
 		if dx > 6 { // To avoid long straight paths, only use random in smaller areas
 			x = midWallPos(x1, x2)
 		} else {
-			x = rWallPos(x1, x2)
+			x = rWallPos(rnd, x1, x2)
 		}
 		// A whole in it:
-		y := rPassPos(y1, y2)
+		y := rPassPos(rnd, y1, y2)
 		for i := y1; i <= y2; i++ {
 			if i != y {
 				lab[i][x] = BlockWall
 			}
 		}
 
-		genLabArea(lab, x1, y1, x, y2)
-		genLabArea(lab, x, y1, x2, y2)
+		genLabArea(lab, rnd, x1, y1, x, y2)
+		genLabArea(lab, rnd, x, y1, x2, y2)
 	} else {
 		// Add horizontal split
 		var y int
 		if dy > 6 { // To avoid long straight paths, only use random in smaller areas
 			y = midWallPos(y1, y2)
 		} else {
-			y = rWallPos(y1, y2)
+			y = rWallPos(rnd, y1, y2)
 		}
 		// A whole in it:
-		x := rPassPos(x1, x2)
+		x := rPassPos(rnd, x1, x2)
 		for i := x1; i <= x2; i++ {
 			if i != x {
 				lab[y][i] = BlockWall
 			}
 		}
 
-		genLabArea(lab, x1, y1, x2, y)
-		genLabArea(lab, x1, y, x2, y2)
+		genLabArea(lab, rnd, x1, y1, x2, y)
+		genLabArea(lab, rnd, x1, y, x2, y2)
 	}
 }
 
 // rWallPos returns a random wall position which is an even number between the specified min and max.
-func rWallPos(min, max int) int {
-	return min + (rand.Intn((max-min)/2-1)+1)*2
+func rWallPos(rnd *rand.Rand, min, max int) int {
+	return min + (rnd.Intn((max-min)/2-1)+1)*2
 }
 
 // midWallPos returns the wall position being at the middle of the specified min and max.
@@ -97,6 +97,6 @@ func midWallPos(min, max int) int {
 }
 
 // rPassPos returns a random passage position which is an odd number between the specified min and max.
-func rPassPos(min, max int) int {
-	return rWallPos(min, max+2) - 1
+func rPassPos(rnd *rand.Rand, min, max int) int {
+	return rWallPos(rnd, min, max+2) - 1
 }
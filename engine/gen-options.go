@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// Placement tells how to place the start and exit blocks in a generated labyrinth.
+type Placement int
+
+const (
+	// PlacementCorners places start at the top left, exit at the bottom right corner.
+	PlacementCorners Placement = iota
+	// PlacementFarthest places start and exit to the farthest pair of blocks.
+	PlacementFarthest
+	// PlacementRandom places start and exit randomly.
+	PlacementRandom
+	// PlacementEdges places start and exit randomly on opposite edges.
+	PlacementEdges
+
+	// PlacementCount is not a valid placement: just to tell how many placements there are
+	PlacementCount
+)
+
+var placementNames = []string{"corners", "farthest", "random", "edges"}
+
+func (p Placement) String() string {
+	if p >= 0 && p < PlacementCount {
+		return placementNames[p]
+	}
+	return fmt.Sprintf("Placement(%d)", p)
+}
+
+// ParsePlacement parses a placement from its name (case insensitive).
+func ParsePlacement(s string) (Placement, error) {
+	for i, name := range placementNames {
+		if strings.EqualFold(name, s) {
+			return Placement(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown placement: %q (valid placements: %s)", s, strings.Join(placementNames, ", "))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p Placement) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *Placement) UnmarshalText(text []byte) (err error) {
+	*p, err = ParsePlacement(string(text))
+	return
+}
+
+// GenOptions holds options of labyrinth generation.
+type GenOptions struct {
+	// Placement of start and exit.
+	Placement Placement `json:"placement,omitempty"`
+
+	// MinSolutionRatio is the minimum length of the solution (the shortest path from start to exit)
+	// relative to the longest possible one in the generated labyrinth (the distance of the farthest blocks).
+	// For example 0.6 requires the solution to be at least 60% of the max.
+	// If no labyrinth satisfying it is generated with the placement, start and exit
+	// are placed to the farthest blocks instead. 0 means no constraint.
+	MinSolutionRatio float64 `json:"minSolutionRatio,omitempty"`
+}
+
+// Validate validates the generation options.
+func (o GenOptions) Validate() error {
+	if o.Placement < 0 || o.Placement >= PlacementCount {
+		return fmt.Errorf("invalid placement: %v", o.Placement)
+	}
+	if o.MinSolutionRatio < 0 || o.MinSolutionRatio > 1 {
+		return fmt.Errorf("min solution ratio must be in range 0..1, got %v", o.MinSolutionRatio)
+	}
+	return nil
+}
+
+// farthest returns the block farthest from the given sources, and its distance.
+func farthest(lab [][]Block, sources ...image.Point) (image.Point, int) {
+	dist := Distances(lab, sources...)
+	var far image.Point
+	max := -1
+	for row := range dist {
+		for col, d := range dist[row] {
+			if d > max {
+				far, max = image.Pt(col, row), d
+			}
+		}
+	}
+	return far, max
+}
+
+// placeStartExit places start and exit in the given lab according to the given placement.
+// ok is false if a placement satisfying the min solution ratio was not found,
+// in which case start and exit are the farthest pair of blocks (satisfying any ratio).
+func (e *Engine) placeStartExit(lab [][]Block, opts GenOptions) (start, exit image.Point, ok bool) {
+	rows, cols := len(lab), len(lab[0])
+	randBlock := func() image.Point {
+		return image.Pt(rPassPos(e.rnd, 0, cols), rPassPos(e.rnd, 0, rows))
+	}
+
+	// Double BFS: the block farthest from any block is one end of the longest path,
+	// and the block farthest from that is the other end.
+	a, _ := farthest(lab, image.Pt(1, 1))
+	b, diameter := farthest(lab, a)
+	minDist := int(opts.MinSolutionRatio * float64(diameter))
+
+	switch opts.Placement {
+	case PlacementFarthest:
+		return a, b, true
+
+	case PlacementRandom:
+		start = randBlock()
+		// Choose a random exit from the blocks far enough:
+		var candidates []image.Point
+		for row, ds := range Distances(lab, start) {
+			for col, d := range ds {
+				if d > 0 && d >= minDist {
+					candidates = append(candidates, image.Pt(col, row))
+				}
+			}
+		}
+		if len(candidates) == 0 {
+			return a, b, false
+		}
+		return start, candidates[e.rnd.Intn(len(candidates))], true
+
+	case PlacementEdges:
+		// Passage positions are odd, so edge blocks are in row / column 1 and rows-2 / cols-2.
+		start, exit = randBlock(), randBlock()
+		if e.rnd.Intn(2) == 0 { // Left and right edges
+			start.X, exit.X = 1, cols-2
+		} else { // Top and bottom edges
+			start.Y, exit.Y = 1, rows-2
+		}
+		if e.rnd.Intn(2) == 0 {
+			start, exit = exit, start
+		}
+
+	default: // PlacementCorners
+		start, exit = image.Pt(1, 1), image.Pt(cols-2, rows-2)
+	}
+
+	if Distances(lab, start)[exit.Y][exit.X] < minDist {
+		return a, b, false
+	}
+	return start, exit, true
+}
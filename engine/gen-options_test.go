@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// newTestLab generates a new lab of the given size.
+func newTestLab(rnd *rand.Rand, rows, cols int) [][]Block {
+	lab := make([][]Block, rows)
+	for row := range lab {
+		lab[row] = make([]Block, cols)
+	}
+	generateLab(lab, rnd)
+	return lab
+}
+
+func TestPlaceStartExit(t *testing.T) {
+	const rows, cols = 15, 15
+	for p := Placement(0); p < PlacementCount; p++ {
+		for seed := int64(1); seed <= 10; seed++ {
+			e := &Engine{rnd: rand.New(rand.NewSource(seed))}
+			lab := newTestLab(e.rnd, rows, cols)
+			opts := GenOptions{Placement: p, MinSolutionRatio: 0.5}
+			start, exit, ok := e.placeStartExit(lab, opts)
+
+			if start == exit || lab[start.Y][start.X] != BlockEmpty || lab[exit.Y][exit.X] != BlockEmpty {
+				t.Errorf("[%v, seed %d] Invalid start and exit: %v, %v", p, seed, start, exit)
+				continue
+			}
+
+			a, _ := farthest(lab, image.Pt(1, 1))
+			_, diameter := farthest(lab, a)
+			dist := Distances(lab, start)[exit.Y][exit.X]
+			if ok && dist < int(opts.MinSolutionRatio*float64(diameter)) {
+				t.Errorf("[%v, seed %d] Expected the ratio to hold, distance %d, diameter %d", p, seed, dist, diameter)
+			}
+			if !ok {
+				// Falls back to the farthest blocks:
+				if dist != diameter {
+					t.Errorf("[%v, seed %d] Expected distance %d, got: %d", p, seed, diameter, dist)
+				}
+				continue
+			}
+
+			switch p {
+			case PlacementCorners:
+				if start != image.Pt(1, 1) || exit != image.Pt(cols-2, rows-2) {
+					t.Errorf("[%v, seed %d] Expected corners, got: %v, %v", p, seed, start, exit)
+				}
+			case PlacementFarthest:
+				// Compare with the max distance of all pairs:
+				max := 0
+				for row := range lab {
+					for col, block := range lab[row] {
+						if _, d := farthest(lab, image.Pt(col, row)); block == BlockEmpty && d > max {
+							max = d
+						}
+					}
+				}
+				if !ok || dist != max {
+					t.Errorf("[%v, seed %d] Expected distance %d, got: %d", p, seed, max, dist)
+				}
+			case PlacementRandom:
+				// Some blocks are always at least half the diameter away.
+				if !ok {
+					t.Errorf("[%v, seed %d] Expected ratio to hold, distance %d, diameter %d", p, seed, dist, diameter)
+				}
+			case PlacementEdges:
+				if min, max := image.Pt(1, 1), image.Pt(cols-2, rows-2); !(start.X == min.X && exit.X == max.X ||
+					start.X == max.X && exit.X == min.X || start.Y == min.Y && exit.Y == max.Y || start.Y == max.Y && exit.Y == min.Y) {
+					t.Errorf("[%v, seed %d] Expected opposite edges, got: %v, %v", p, seed, start, exit)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateUnsatisfiableRatio(t *testing.T) {
+	// A ratio of 1 is only satisfied by the farthest blocks, which other placements rarely hit.
+	for p := Placement(0); p < PlacementCount; p++ {
+		for seed := int64(1); seed <= 10; seed++ {
+			e := &Engine{rnd: rand.New(rand.NewSource(seed))}
+			lab, start, exit := e.generate(15, 21, GenOptions{Placement: p, MinSolutionRatio: 1}, 0)
+
+			_, diameter := farthest(lab, start)
+			if dist := Distances(lab, start)[exit.Y][exit.X]; start == exit || dist != diameter {
+				t.Errorf("[%v, seed %d] Expected distance %d, got: %d (start %v, exit %v)", p, seed, diameter, dist, start, exit)
+			}
+		}
+	}
+}
+
+func TestGenAttempts(t *testing.T) {
+	cases := []struct {
+		rows, cols, exp int
+	}{
+		{9, 9, maxGenAttempts},
+		{201, 201, maxGenAttempts},
+		{501, 501, 4},
+		{LabSizeMax, LabSizeMax, 1},
+	}
+
+	for _, c := range cases {
+		if got := genAttempts(c.rows, c.cols); got != c.exp {
+			t.Errorf("[%dx%d] Expected %d, got: %d", c.rows, c.cols, c.exp, got)
+		}
+	}
+}
+
+func TestParsePlacement(t *testing.T) {
+	for p := Placement(0); p < PlacementCount; p++ {
+		if p2, err := ParsePlacement(p.String()); p2 != p || err != nil {
+			t.Errorf("Expected %v, got: %v, err: %v", p, p2, err)
+		}
+	}
+	if p, err := ParsePlacement("FARTHEST"); p != PlacementFarthest || err != nil {
+		t.Errorf("Expected %v, got: %v, err: %v", PlacementFarthest, p, err)
+	}
+	if _, err := ParsePlacement("center"); err == nil {
+		t.Errorf("Expected error for unknown placement")
+	}
+}
+
+func TestGenOptionsValidate(t *testing.T) {
+	cases := []struct {
+		opts  GenOptions
+		valid bool
+	}{
+		{GenOptions{}, true},
+		{GenOptions{Placement: PlacementEdges, MinSolutionRatio: 1}, true},
+		{GenOptions{Placement: PlacementCount}, false},
+		{GenOptions{Placement: -1}, false},
+		{GenOptions{MinSolutionRatio: -0.1}, false},
+		{GenOptions{MinSolutionRatio: 1.1}, false},
+	}
+	for _, c := range cases {
+		if err := c.opts.Validate(); (err == nil) != c.valid {
+			t.Errorf("[%+v] Expected valid: %v, got error: %v", c.opts, c.valid, err)
+		}
+	}
+}
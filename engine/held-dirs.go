@@ -0,0 +1,70 @@
+package engine
+
+// holdDir marks the given direction key as held down.
+func (e *Engine) holdDir(dir Dir) {
+	e.releaseDirs(map[Dir]bool{dir: true})
+	e.heldDirs = append(e.heldDirs, dir)
+}
+
+// releaseDirs marks the given direction keys as released.
+func (e *Engine) releaseDirs(dirs map[Dir]bool) {
+	held := e.heldDirs[:0]
+	for _, dir := range e.heldDirs {
+		if !dirs[dir] {
+			held = append(held, dir)
+		}
+	}
+	e.heldDirs = held
+}
+
+// followHeldDirs sets the next target of Gopher according to the held direction keys.
+// It is called when Gopher has reached its target and there are no more queued targets.
+//
+// The buffered turn is taken first if possible, then the held directions,
+// the most recently pressed one first. So Gopher keeps moving while a direction key is held,
+// and turns at the next junction into a direction pressed in advance.
+// If Gopher stops, the buffered turn is dropped.
+func (e *Engine) followHeldDirs() {
+	Gopher := e.Model.Gopher
+	lab := e.Model.Lab
+	col, row := Gopher.TargetPos.X/BlockSize, Gopher.TargetPos.Y/BlockSize
+
+	// move sets the next target in the given direction if the way is free.
+	move := func(dir Dir) bool {
+		dcol, drow := dirDelta(dir)
+		if lab[row+drow][col+dcol] != BlockEmpty {
+			return false
+		}
+		Gopher.TargetPos.X = (col+dcol)*BlockSize + BlockSize/2
+		Gopher.TargetPos.Y = (row+drow)*BlockSize + BlockSize/2
+		return true
+	}
+
+	if e.turn != DirCount && move(e.turn) {
+		e.turn = DirCount
+		return
+	}
+	for i := len(e.heldDirs) - 1; i >= 0; i-- {
+		if move(e.heldDirs[i]) {
+			return
+		}
+	}
+	if len(e.heldDirs) == 0 {
+		e.turn = DirCount
+	}
+}
+
+// dirDelta returns the column and row deltas of a step in the given direction.
+func dirDelta(dir Dir) (dcol, drow int) {
+	switch dir {
+	case DirLeft:
+		dcol = -1
+	case DirRight:
+		dcol = 1
+	case DirUp:
+		drow = -1
+	case DirDown:
+		drow = 1
+	}
+	return
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+// newHeldDirsTestEngine returns an engine with a started game on a small lab map without Bulldogs.
+func newHeldDirsTestEngine(t *testing.T) *Engine {
+	lm, err := ParseLabMap(strings.NewReader(`#######
+#S....#
+#.###.#
+#....E#
+#######
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse lab map: %v", err)
+	}
+	e := NewEngine(func() {})
+	e.NewGame(GameConfig{Difficulty: Difficulties[0], Speed: Speeds[SpeedDefaultIdx], Seed: 1, Map: lm})
+	e.Step()
+	return e
+}
+
+// gopherAt tells if Gopher is at the center of the given block.
+func gopherAt(m *Model, block image.Point) bool {
+	c := BlockCenter(block)
+	return int(m.Gopher.Pos.X) == c.X && int(m.Gopher.Pos.Y) == c.Y
+}
+
+func TestHeldDirsBufferedTurn(t *testing.T) {
+	e := newHeldDirsTestEngine(t)
+	m := e.Model
+
+	e.SendKey(Key{DirKeys: map[Dir]bool{DirRight: true}, Hold: true})
+	for i := 0; !gopherAt(m, image.Pt(2, 1)); i++ {
+		if i > 100 {
+			t.Fatalf("Gopher did not move right")
+		}
+		e.Step()
+	}
+
+	// The wall is below, the turn is buffered and taken at the first free block:
+	e.SendKey(Key{DirKeys: map[Dir]bool{DirDown: true}})
+	for i := 0; i < 100; i++ {
+		e.Step()
+		if b := BlockOf(int(m.Gopher.Pos.X), int(m.Gopher.Pos.Y)); b.X < 5 && b.Y > 1 {
+			t.Fatalf("Gopher turned at the wrong block: %v", b)
+		}
+	}
+	// Right is still held but blocked: Gopher stops after the turn.
+	if !gopherAt(m, image.Pt(5, 2)) {
+		t.Errorf("Expected Gopher at %v, got: %v", BlockCenter(image.Pt(5, 2)), m.Gopher.Pos)
+	}
+}
+
+func TestHeldDirsRelease(t *testing.T) {
+	e := newHeldDirsTestEngine(t)
+	m := e.Model
+
+	e.SendKey(Key{DirKeys: map[Dir]bool{DirRight: true}, Hold: true})
+	for i := 0; i < 15; i++ {
+		e.Step()
+	}
+	// Gopher is between blocks, it stops at the block it is heading to:
+	target := BlockOf(m.Gopher.TargetPos.X, m.Gopher.TargetPos.Y)
+	if target.X < 2 || target.X > 3 {
+		t.Fatalf("Expected Gopher moving right, target: %v", target)
+	}
+	e.SendKey(Key{DirKeys: map[Dir]bool{DirRight: true}, Released: true})
+	for i := 0; i < 100; i++ {
+		e.Step()
+	}
+	if !gopherAt(m, target) {
+		t.Errorf("Expected Gopher at %v, got: %v", BlockCenter(target), m.Gopher.Pos)
+	}
+}
@@ -0,0 +1,55 @@
+package engine
+
+import "math"
+
+const (
+	// HintDuration is the game time a hint is shown for, in frames.
+	HintDuration = 60
+
+	// ScorePerBlock is the score for each block of the solution of the lab
+	// when the exit is reached in par time without using hints.
+	ScorePerBlock = 10
+)
+
+// RequestHint enqueues a request for a hint: the shortest path from Gopher to the exit
+// is stored in Model.Hint for HintDuration frames.
+// Nothing happens if there are no hints left.
+func (e *Engine) RequestHint() {
+	e.cmdChan <- &HintRequest{}
+}
+
+// handleHint handles a hint request.
+func (e *Engine) handleHint() {
+	m := e.Model
+
+	if m.Dead || m.Won || m.HintsLeft <= 0 {
+		return
+	}
+
+	from := BlockOf(int(m.Gopher.Pos.X), int(m.Gopher.Pos.Y))
+	m.Hint = FindPath(m.Lab, from, m.ExitBlock, nil)
+	m.HintUntil = m.Frame + HintDuration
+	m.HintsLeft--
+	m.HintsUsed++
+}
+
+// score calculates the score of a won game.
+//
+// The base score is ScorePerBlock for each block of the solution.
+// It is reduced proportionally if the game took longer than par time
+// (the time needed to walk the solution without stopping),
+// and by the difficulty's hint penalty for each hint used.
+func (e *Engine) score() int {
+	m := e.Model
+
+	base := float64(e.solutionLen * ScorePerBlock)
+
+	// Frames needed to walk a block:
+	frameBlock := BlockSize / (v * dt)
+	parFrames := float64(e.solutionLen) * frameBlock
+	timeFactor := math.Min(1, parFrames/float64(m.Frame+1))
+
+	hintFactor := math.Max(0, 1-float64(m.HintsUsed)*e.cfg.Difficulty.params.HintPenalty)
+
+	return int(math.Round(base * timeFactor * hintFactor))
+}
@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newHintTestEngine returns an engine with a started game without Bulldogs, with 2 hints and 0.25 hint penalty.
+func newHintTestEngine(t *testing.T) *Engine {
+	p := BaseDifficultyParams()
+	p.Hints, p.HintPenalty = 2, 0.25
+	d, err := NewDifficultyParams("Test", p)
+	if err != nil {
+		t.Fatalf("Failed to create difficulty: %v", err)
+	}
+	e := NewEngine(func() {})
+	e.NewGame(GameConfig{Difficulty: d, LabSize: LabSizes[1], Speed: Speeds[SpeedDefaultIdx], Seed: 1})
+	e.Step()
+	return e
+}
+
+func TestHint(t *testing.T) {
+	e := newHintTestEngine(t)
+	m := e.Model
+
+	e.RequestHint()
+	e.Step()
+	exp := FindPath(m.Lab, m.StartBlock, m.ExitBlock, nil)
+	if !reflect.DeepEqual(m.Hint, exp) {
+		t.Errorf("Expected hint %v, got: %v", exp, m.Hint)
+	}
+	if m.HintsLeft != 1 || m.HintsUsed != 1 || m.HintUntil != 1+HintDuration {
+		t.Errorf("Expected 1 hint left, 1 used, until %d, got: %d, %d, %d", 1+HintDuration, m.HintsLeft, m.HintsUsed, m.HintUntil)
+	}
+
+	for m.Frame < m.HintUntil-1 {
+		e.Step()
+	}
+	if m.Hint == nil {
+		t.Errorf("Expected hint before frame %d", m.HintUntil)
+	}
+	e.Step()
+	if m.Hint != nil {
+		t.Errorf("Expected no hint at frame %d", m.Frame)
+	}
+
+	e.RequestHint()
+	e.RequestHint() // No hints left, ignored
+	e.Step()
+	if m.HintsLeft != 0 || m.HintsUsed != 2 {
+		t.Errorf("Expected no hints left, 2 used, got: %d, %d", m.HintsLeft, m.HintsUsed)
+	}
+}
+
+func TestScore(t *testing.T) {
+	e := newHintTestEngine(t)
+	m := e.Model
+	e.solutionLen = 10 // Par time is 100 frames
+
+	cases := []struct {
+		frame, hintsUsed, score int
+	}{
+		{50, 0, 100},
+		{99, 0, 100},
+		{199, 0, 50},
+		{99, 1, 75},
+		{199, 2, 25},
+		{99, 4, 0},
+		{99, 5, 0},
+	}
+	for _, c := range cases {
+		m.Frame, m.HintsUsed = c.frame, c.hintsUsed
+		if score := e.score(); score != c.score {
+			t.Errorf("[frame %d, hints %d] Expected score %d, got: %d", c.frame, c.hintsUsed, c.score, score)
+		}
+	}
+}
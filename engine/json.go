@@ -0,0 +1,17 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// unmarshalStrict decodes the JSON data into v like json.Unmarshal,
+// but unknown fields are rejected (so misspelled fields don't go unnoticed).
+//
+// json.Unmarshaler implementations use this because the settings of the json.Decoder
+// they are called from (e.g. DisallowUnknownFields) do not apply to them.
+func unmarshalStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
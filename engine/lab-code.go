@@ -0,0 +1,167 @@
+package engine
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"io"
+	"io/ioutil"
+)
+
+// labCodeVersion is the version of the lab code format, the first byte of the encoded data.
+const labCodeVersion = 1
+
+// Code returns the lab code of the lab map: a short, URL-safe string that can be shared
+// and decoded with ParseLabCode. Unlike seeds, lab codes do not depend on the lab generator.
+//
+// The lab code is the base64url encoded, DEFLATE compressed form of the following data:
+// version, rows, cols, start, exit, number of spawns and spawns (as uvarints),
+// the wall bits of the inner blocks (the border is always wall) and a CRC-32 checksum.
+func (lm *LabMap) Code() string {
+	rows, cols := len(lm.Lab), len(lm.Lab[0])
+
+	data := []byte{labCodeVersion}
+	putUvarint := func(x int) {
+		data = append(data, make([]byte, binary.MaxVarintLen64)...)
+		n := binary.PutUvarint(data[len(data)-binary.MaxVarintLen64:], uint64(x))
+		data = data[:len(data)-binary.MaxVarintLen64+n]
+	}
+	putPoint := func(p image.Point) {
+		putUvarint(p.Y)
+		putUvarint(p.X)
+	}
+
+	putUvarint(rows)
+	putUvarint(cols)
+	putPoint(lm.Start)
+	putPoint(lm.Exit)
+	putUvarint(len(lm.Spawns))
+	for _, s := range lm.Spawns {
+		putPoint(s)
+	}
+
+	var b byte
+	var bits uint
+	for row := 1; row < rows-1; row++ {
+		for col := 1; col < cols-1; col++ {
+			if lm.Lab[row][col] == BlockWall {
+				b |= 1 << bits
+			}
+			if bits++; bits == 8 {
+				data = append(data, b)
+				b, bits = 0, 0
+			}
+		}
+	}
+	if bits > 0 {
+		data = append(data, b)
+	}
+
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(data))
+	data = append(data, sum[:]...)
+
+	buf := &bytes.Buffer{}
+	fw, _ := flate.NewWriter(buf, flate.BestCompression) // Error only for invalid level
+	fw.Write(data)                                       // bytes.Buffer never fails
+	fw.Close()
+
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
+
+// ParseLabCode decodes a lab map from a lab code produced by LabMap.Code.
+// The checksum and the decoded lab map are validated.
+func ParseLabCode(code string) (*LabMap, error) {
+	compressed, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lab code: %v", err)
+	}
+
+	// Limit decompressed size: max lab size with a generous allowance for the header.
+	const maxSize = LabSizeMax*LabSizeMax/8 + 1<<16
+	data, err := ioutil.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid lab code: %v", err)
+	}
+	if len(data) > maxSize {
+		return nil, errors.New("invalid lab code: too big")
+	}
+	if len(data) < 5 {
+		return nil, errors.New("invalid lab code: too short")
+	}
+
+	data, sum := data[:len(data)-4], data[len(data)-4:]
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(sum) {
+		return nil, errors.New("invalid lab code: checksum mismatch")
+	}
+	if data[0] != labCodeVersion {
+		return nil, fmt.Errorf("invalid lab code: unsupported version %d", data[0])
+	}
+
+	r := bytes.NewReader(data[1:])
+	getUvarint := func() int {
+		x, e := binary.ReadUvarint(r)
+		if e != nil && err == nil {
+			err = e
+		}
+		if x > LabSizeMax*LabSizeMax && err == nil {
+			err = errors.New("value out of range")
+		}
+		return int(x)
+	}
+	getPoint := func() image.Point {
+		row := getUvarint()
+		return image.Pt(getUvarint(), row)
+	}
+
+	lm := &LabMap{}
+	rows, cols := getUvarint(), getUvarint()
+	lm.Start, lm.Exit = getPoint(), getPoint()
+	if n := getUvarint(); err == nil {
+		if n > r.Len() {
+			err = errors.New("too many spawns")
+		} else {
+			lm.Spawns = make([]image.Point, n)
+			for i := range lm.Spawns {
+				lm.Spawns[i] = getPoint()
+			}
+		}
+	}
+	if err == nil && (rows < LabSizeMin || rows > LabSizeMax || cols < LabSizeMin || cols > LabSizeMax) {
+		err = fmt.Errorf("lab size out of range: %dx%d", cols, rows)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid lab code: %v", err)
+	}
+
+	bitsData, _ := ioutil.ReadAll(r)
+	if len(bitsData) != ((rows-2)*(cols-2)+7)/8 {
+		return nil, errors.New("invalid lab code: wall data size mismatch")
+	}
+
+	lm.Lab = make([][]Block, rows)
+	i := 0
+	for row := range lm.Lab {
+		lm.Lab[row] = make([]Block, cols)
+		for col := range lm.Lab[row] {
+			if row == 0 || col == 0 || row == rows-1 || col == cols-1 {
+				lm.Lab[row][col] = BlockWall
+				continue
+			}
+			if bitsData[i/8]&(1<<uint(i%8)) != 0 {
+				lm.Lab[row][col] = BlockWall
+			}
+			i++
+		}
+	}
+
+	if err := lm.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid lab code: %v", err)
+	}
+	return lm, nil
+}
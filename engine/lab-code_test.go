@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLabCodeParse(t *testing.T) {
+	lm, err := ParseLabMap(strings.NewReader(testLabMapText))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngine(func() {})
+	e.NewGame(GameConfig{Difficulty: Difficulties[DifficultyDefaultIdx], LabSize: LabSizes[len(LabSizes)-1], Speed: Speeds[SpeedDefaultIdx], Seed: 1})
+	e.Step()
+
+	for i, lm := range []*LabMap{lm, e.Model.LabMap()} {
+		code := lm.Code()
+		if strings.ContainsAny(code, "+/=") {
+			t.Errorf("[%d] Expected URL-safe code, got: %s", i, code)
+		}
+		lm2, err := ParseLabCode(code)
+		if err != nil {
+			t.Errorf("[%d] Parse of code failed: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(lm.Lab, lm2.Lab) || lm.Start != lm2.Start || lm.Exit != lm2.Exit ||
+			len(lm.Spawns) != len(lm2.Spawns) || len(lm.Spawns) > 0 && !reflect.DeepEqual(lm.Spawns, lm2.Spawns) {
+			t.Errorf("[%d] Parsed map differs from the encoded one", i)
+		}
+	}
+}
+
+func TestParseLabCodeErrors(t *testing.T) {
+	lm, err := ParseLabMap(strings.NewReader(testLabMapText))
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed, err := base64.RawURLEncoding.DecodeString(lm.Code())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := data[:len(data)-4]
+
+	encode := func(data []byte) string {
+		buf := &bytes.Buffer{}
+		fw, _ := flate.NewWriter(buf, flate.BestCompression)
+		fw.Write(data)
+		fw.Close()
+		return base64.RawURLEncoding.EncodeToString(buf.Bytes())
+	}
+	// modified returns the code of the payload modified by f, with a valid checksum.
+	modified := func(f func(p []byte) []byte) string {
+		p := f(append([]byte(nil), payload...))
+		var sum [4]byte
+		binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(p))
+		return encode(append(p, sum[:]...))
+	}
+
+	badSum := append([]byte(nil), data...)
+	badSum[len(badSum)-1] ^= 0xff
+
+	cases := []struct {
+		name   string
+		code   string
+		errMsg string
+	}{
+		{"empty", "", "invalid lab code"},
+		{"bad characters", "ab+c/d=", "illegal base64 data"},
+		{"not compressed", base64.RawURLEncoding.EncodeToString([]byte("not a lab")), "invalid lab code"},
+		{"too short", encode([]byte{1, 2}), "too short"},
+		{"bad checksum", encode(badSum), "checksum mismatch"},
+		{"bad version", modified(func(p []byte) []byte { p[0] = 2; return p }), "unsupported version 2"},
+		{"size too small", modified(func(p []byte) []byte { p[1] = 3; return p }), "lab size out of range"},
+		{"size too big", modified(func(p []byte) []byte {
+			return append([]byte{p[0], 0xea, 0x07}, p[2:]...) // 1002 rows
+		}), "lab size out of range"},
+		{"wall data truncated", modified(func(p []byte) []byte { return p[:len(p)-1] }), "wall data size mismatch"},
+		{"wall data too long", modified(func(p []byte) []byte { return append(p, 0) }), "wall data size mismatch"},
+		{"start on wall", modified(func(p []byte) []byte { p[len(p)-1] = 0xff; p[len(p)-2] = 0xff; return p }), "must be on an empty block"},
+		{"truncated header", modified(func(p []byte) []byte { return p[:3] }), "invalid lab code"},
+	}
+
+	for _, c := range cases {
+		_, err := ParseLabCode(c.code)
+		if err == nil {
+			t.Errorf("[%s] Expected error", c.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.errMsg) {
+			t.Errorf("[%s] Expected error containing %q, got: %v", c.name, c.errMsg, err)
+		}
+	}
+}
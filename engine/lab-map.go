@@ -0,0 +1,254 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+)
+
+// Characters of the plain text lab map format.
+const (
+	MapWall    = '#'
+	MapEmpty   = '.'
+	MapStart   = 'S'
+	MapExit    = 'E'
+	MapSpawn   = 'B'
+	MapComment = ';' // Lines starting with this are comments
+)
+
+// LabMap describes a labyrinth with start, exit and Bulldog spawn blocks.
+// It can be used to start a game with a hand-authored (or previously exported) labyrinth.
+//
+// The plain text format has one line for each row of the labyrinth, and one character
+// for each block:
+//
+//	#  wall
+//	.  empty (space is also accepted)
+//	S  start (empty block)
+//	E  exit (empty block)
+//	B  Bulldog spawn (empty block)
+//
+// Lines starting with ';' are comments. The map ends at the first empty line after its rows,
+// only empty lines and comments may follow. The labyrinth must be rectangular, surrounded by walls,
+// and must contain exactly one start and one exit, reachable from the start.
+//
+// The game has no other kinds of tiles or items, so the format has no characters for them
+// (any other character is rejected). Adding them is out of scope until the engine supports them.
+type LabMap struct {
+	// Blocks of the lab. First indexed by row, then by column.
+	Lab [][]Block
+
+	// Start and Exit blocks, in block coordinates (X: column, Y: row).
+	Start, Exit image.Point
+
+	// Spawns holds the spawn blocks of the Bulldogs. If empty, Bulldogs are spawned
+	// randomly according to the difficulty.
+	Spawns []image.Point
+}
+
+// ParseLabMap parses a lab map in plain text format.
+func ParseLabMap(r io.Reader) (*LabMap, error) {
+	lm := &LabMap{}
+	var hasStart, hasExit bool
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 4*LabSizeMax)
+	lineNum, ended := 0, false
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, string(MapComment)) {
+			continue
+		}
+		if ended {
+			if strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("line %d: unexpected content after the end of the map", lineNum)
+			}
+			continue
+		}
+		if line == "" && len(lm.Lab) > 0 {
+			ended = true // End of map
+			continue
+		}
+		if line == "" {
+			continue // Skip leading empty lines
+		}
+
+		row := len(lm.Lab)
+		blocks := make([]Block, 0, len(line))
+		for col, ch := range []byte(line) {
+			var b Block = BlockEmpty
+			p := image.Pt(col, row)
+			switch ch {
+			case MapWall:
+				b = BlockWall
+			case MapEmpty, ' ':
+			case MapStart:
+				if hasStart {
+					return nil, fmt.Errorf("line %d: multiple starts", lineNum)
+				}
+				lm.Start, hasStart = p, true
+			case MapExit:
+				if hasExit {
+					return nil, fmt.Errorf("line %d: multiple exits", lineNum)
+				}
+				lm.Exit, hasExit = p, true
+			case MapSpawn:
+				lm.Spawns = append(lm.Spawns, p)
+			default:
+				return nil, fmt.Errorf("line %d: invalid character %q at column %d", lineNum, ch, col+1)
+			}
+			blocks = append(blocks, b)
+		}
+		if row > 0 && len(blocks) != len(lm.Lab[0]) {
+			return nil, fmt.Errorf("line %d: width %d differs from width of first row %d", lineNum, len(blocks), len(lm.Lab[0]))
+		}
+		lm.Lab = append(lm.Lab, blocks)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(lm.Lab) == 0:
+		return nil, errors.New("empty map")
+	case !hasStart:
+		return nil, errors.New("missing start")
+	case !hasExit:
+		return nil, errors.New("missing exit")
+	}
+
+	if err := lm.Validate(); err != nil {
+		return nil, err
+	}
+	return lm, nil
+}
+
+// Validate validates the lab map: checks its size, that it's surrounded by walls,
+// that start, exit and spawns are inside the lab on empty blocks,
+// and that the exit is reachable from the start.
+func (lm *LabMap) Validate() error {
+	rows := len(lm.Lab)
+	if rows < LabSizeMin || rows > LabSizeMax {
+		return fmt.Errorf("rows must be in range %d..%d, got %d", LabSizeMin, LabSizeMax, rows)
+	}
+	cols := len(lm.Lab[0])
+	if cols < LabSizeMin || cols > LabSizeMax {
+		return fmt.Errorf("cols must be in range %d..%d, got %d", LabSizeMin, LabSizeMax, cols)
+	}
+
+	for row := range lm.Lab {
+		if len(lm.Lab[row]) != cols {
+			return fmt.Errorf("row %d: width %d differs from width of first row %d", row+1, len(lm.Lab[row]), cols)
+		}
+		for col, b := range lm.Lab[row] {
+			if b != BlockEmpty && b != BlockWall {
+				return fmt.Errorf("invalid block at row %d, column %d: %d", row+1, col+1, b)
+			}
+			if (row == 0 || col == 0 || row == rows-1 || col == cols-1) && b != BlockWall {
+				return fmt.Errorf("lab must be surrounded by walls, no wall at row %d, column %d", row+1, col+1)
+			}
+		}
+	}
+
+	check := func(what string, p image.Point) error {
+		if p.X <= 0 || p.Y <= 0 || p.X >= cols-1 || p.Y >= rows-1 || lm.Lab[p.Y][p.X] != BlockEmpty {
+			return fmt.Errorf("%s must be on an empty block inside the lab, got row %d, column %d", what, p.Y+1, p.X+1)
+		}
+		return nil
+	}
+	if err := check("start", lm.Start); err != nil {
+		return err
+	}
+	if err := check("exit", lm.Exit); err != nil {
+		return err
+	}
+	if lm.Start == lm.Exit {
+		return errors.New("start and exit must be different blocks")
+	}
+	if Distances(lm.Lab, lm.Start)[lm.Exit.Y][lm.Exit.X] < 0 {
+		return errors.New("exit is not reachable from the start")
+	}
+	for _, s := range lm.Spawns {
+		if err := check("spawn", s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Format writes the lab map in plain text format.
+func (lm *LabMap) Format(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	line := make([]byte, 0, len(lm.Lab[0])+1)
+	for row := range lm.Lab {
+		line = line[:0]
+		for _, b := range lm.Lab[row] {
+			if b == BlockWall {
+				line = append(line, MapWall)
+			} else {
+				line = append(line, MapEmpty)
+			}
+		}
+		for _, s := range lm.Spawns {
+			if s.Y == row {
+				line[s.X] = MapSpawn
+			}
+		}
+		if lm.Start.Y == row {
+			line[lm.Start.X] = MapStart
+		}
+		if lm.Exit.Y == row {
+			line[lm.Exit.X] = MapExit
+		}
+		line = append(line, '\n')
+		if _, err := bw.Write(line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// MarshalText implements encoding.TextMarshaler, using the plain text format.
+func (lm *LabMap) MarshalText() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := lm.Format(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, using the plain text format.
+func (lm *LabMap) UnmarshalText(text []byte) error {
+	lm2, err := ParseLabMap(bytes.NewReader(text))
+	if err != nil {
+		return err
+	}
+	*lm = *lm2
+	return nil
+}
+
+// copy returns a deep copy of the lab map.
+func (lm *LabMap) copy() *LabMap {
+	lm2 := &LabMap{
+		Lab:    make([][]Block, len(lm.Lab)),
+		Start:  lm.Start,
+		Exit:   lm.Exit,
+		Spawns: append([]image.Point(nil), lm.Spawns...),
+	}
+	for row := range lm.Lab {
+		lm2.Lab[row] = append([]Block(nil), lm.Lab[row]...)
+	}
+	return lm2
+}
+
+// LabMap returns the lab map of the current game: the labyrinth, start, exit
+// and the initial blocks of the Bulldogs.
+// The model must be locked for reading.
+func (m *Model) LabMap() *LabMap {
+	lm := &LabMap{Lab: m.Lab, Start: m.StartBlock, Exit: m.ExitBlock, Spawns: m.SpawnBlocks}
+	return lm.copy()
+}
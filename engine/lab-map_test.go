@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"bytes"
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testLabMapText is a small lab map using all characters of the format.
+const testLabMapText = `#######
+#S..#.#
+#.#.#B#
+#.#...#
+#B#.#E#
+#######
+`
+
+// formatLabMap returns the lab map formatted as text.
+func formatLabMap(t *testing.T, lm *LabMap) string {
+	buf := &bytes.Buffer{}
+	if err := lm.Format(buf); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	return buf.String()
+}
+
+func TestParseLabMap(t *testing.T) {
+	lm, err := ParseLabMap(strings.NewReader("; A comment\n\n" + testLabMapText + "\n; A trailing comment\n\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(lm.Lab) != 6 || len(lm.Lab[0]) != 7 {
+		t.Errorf("Expected 6 rows and 7 cols, got: %d rows, %d cols", len(lm.Lab), len(lm.Lab[0]))
+	}
+	if lm.Lab[1][4] != BlockWall || lm.Lab[1][2] != BlockEmpty {
+		t.Errorf("Unexpected blocks in row 2: %v", lm.Lab[1])
+	}
+	if exp := image.Pt(1, 1); lm.Start != exp {
+		t.Errorf("Expected start %v, got: %v", exp, lm.Start)
+	}
+	if exp := image.Pt(5, 4); lm.Exit != exp {
+		t.Errorf("Expected exit %v, got: %v", exp, lm.Exit)
+	}
+	if exp := []image.Point{{5, 2}, {1, 4}}; !reflect.DeepEqual(lm.Spawns, exp) {
+		t.Errorf("Expected spawns %v, got: %v", exp, lm.Spawns)
+	}
+}
+
+func TestLabMapFormatParse(t *testing.T) {
+	e := NewEngine(func() {})
+	e.NewGame(GameConfig{Difficulty: Difficulties[DifficultyDefaultIdx], LabSize: LabSizes[LabSizeDefaultIdx], Speed: Speeds[SpeedDefaultIdx], Seed: 1})
+	e.Step()
+
+	for i, text := range []string{testLabMapText, formatLabMap(t, e.Model.LabMap())} {
+		lm, err := ParseLabMap(strings.NewReader(text))
+		if err != nil {
+			t.Errorf("[%d] Parse failed: %v", i, err)
+			continue
+		}
+		if text2 := formatLabMap(t, lm); text2 != text {
+			t.Errorf("[%d] Expected same text when formatted again:\n%s\ngot:\n%s", i, text, text2)
+		}
+	}
+}
+
+func TestParseLabMapErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		text   string
+		errMsg string
+	}{
+		{"empty", "", "empty map"},
+		{"comments only", "; nothing here\n", "empty map"},
+		{"invalid character", "#####\n#S.E#\n#.x.#\n#...#\n#####\n", `invalid character 'x' at column 3`},
+		{"item character", "#####\n#S.E#\n#.*.#\n#...#\n#####\n", `invalid character '*'`},
+		{"not rectangular", "#####\n#S.E#\n#..#\n#...#\n#####\n", "width 4 differs from width of first row 5"},
+		{"missing start", "#####\n#..E#\n#...#\n#...#\n#####\n", "missing start"},
+		{"missing exit", "#####\n#S..#\n#...#\n#...#\n#####\n", "missing exit"},
+		{"multiple starts", "#####\n#S.E#\n#.S.#\n#...#\n#####\n", "multiple starts"},
+		{"multiple exits", "#####\n#S.E#\n#.E.#\n#...#\n#####\n", "multiple exits"},
+		{"not walled", "#####\n#S.E.\n#...#\n#...#\n#####\n", "must be surrounded by walls"},
+		{"too few rows", "#####\n#S.E#\n#####\n", "rows must be in range"},
+		{"too few cols", "####\n#SE#\n#..#\n#..#\n####\n", "cols must be in range"},
+		{"start on border", "#S###\n#..E#\n#...#\n#...#\n#####\n", "must be surrounded by walls"},
+		{"content after map", "#####\n#S.E#\n#...#\n#...#\n#####\n\n#####\n", "line 7: unexpected content after the end"},
+		{"unreachable exit", "#####\n#S#E#\n#.#.#\n#.#.#\n#####\n", "exit is not reachable"},
+	}
+
+	for _, c := range cases {
+		_, err := ParseLabMap(strings.NewReader(c.text))
+		if err == nil {
+			t.Errorf("[%s] Expected error", c.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.errMsg) {
+			t.Errorf("[%s] Expected error containing %q, got: %v", c.name, c.errMsg, err)
+		}
+	}
+}
@@ -1,6 +1,17 @@
 package engine
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// LabSizeMin is the minimum number of rows and columns of a labyrinth.
+	LabSizeMin = 5
+	// LabSizeMax is the maximum number of rows and columns of a labyrinth.
+	LabSizeMax = 1001
+)
 
 // LabSize difines choosable labyrinth sizes..
 type LabSize struct {
@@ -10,10 +21,73 @@ type LabSize struct {
 	Default bool
 }
 
+// NewLabSize creates a new LabSize.
+// rows and cols must be odd numbers in the range of LabSizeMin..LabSizeMax.
+func NewLabSize(name string, rows, cols int) (*LabSize, error) {
+	if name == "" {
+		return nil, errors.New("lab size name must not be empty")
+	}
+	check := func(what string, n int) error {
+		if n < LabSizeMin || n > LabSizeMax {
+			return fmt.Errorf("lab size %q: %s must be in range %d..%d, got %d", name, what, LabSizeMin, LabSizeMax, n)
+		}
+		if n%2 == 0 {
+			return fmt.Errorf("lab size %q: %s must be odd, got %d", name, what, n)
+		}
+		return nil
+	}
+	if err := check("rows", rows); err != nil {
+		return nil, err
+	}
+	if err := check("cols", cols); err != nil {
+		return nil, err
+	}
+
+	return &LabSize{Name: name, rows: rows, cols: cols}, nil
+}
+
+// Rows returns the number of rows of the labyrinth.
+func (l *LabSize) Rows() int {
+	return l.rows
+}
+
+// Cols returns the number of columns of the labyrinth.
+func (l *LabSize) Cols() int {
+	return l.cols
+}
+
 func (l *LabSize) String() string {
 	return fmt.Sprintf("%s (%dx%d)", l.Name, l.rows, l.cols)
 }
 
+// labSizeJSON is the JSON representation of LabSize.
+type labSizeJSON struct {
+	Name    string `json:"name"`
+	Rows    int    `json:"rows"`
+	Cols    int    `json:"cols"`
+	Default bool   `json:"default,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (l *LabSize) MarshalJSON() ([]byte, error) {
+	return json.Marshal(labSizeJSON{Name: l.Name, Rows: l.rows, Cols: l.cols, Default: l.Default})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The decoded values are validated.
+func (l *LabSize) UnmarshalJSON(data []byte) error {
+	var lj labSizeJSON
+	if err := unmarshalStrict(data, &lj); err != nil {
+		return err
+	}
+	l2, err := NewLabSize(lj.Name, lj.Rows, lj.Cols)
+	if err != nil {
+		return err
+	}
+	*l = *l2
+	l.Default = lj.Default
+	return nil
+}
+
 // LabSizes is a slice of all, ordered lab sizes.
 var LabSizes = []*LabSize{
 	&LabSize{Name: "XS", rows: 9, cols: 9},
@@ -18,14 +18,32 @@ type Model struct {
 	// Can be used to invalidate caches when its value changes.
 	Counter int
 
+	// Seed is the seed of the random source of the current game.
+	// The same config and seed generate the same labyrinth and Bulldogs.
+	Seed int64
+
+	// Frame is the number of iterations calculated in the current game.
+	// It stops increasing when the game is over (Gopher is dead or won),
+	// the iteration in which the game ended is included.
+	Frame int
+
+	// Difficulty of the current game.
+	Difficulty *Difficulty
+
 	// Size of the labyrinth in blocks.
 	Rows, Cols int
 
 	// Blocks of the lab. First indexed by row, then by column.
 	Lab [][]Block
 
-	// ExitPos: the position Gopher has to reach to win the game.
-	ExitPos image.Point
+	// StartBlock is the block where Gopher starts, in block coordinates (X: column, Y: row).
+	StartBlock image.Point
+
+	// ExitBlock is the block Gopher has to reach to win the game, in block coordinates (X: column, Y: row).
+	ExitBlock image.Point
+
+	// SpawnBlocks holds the blocks where the Bulldogs were spawned, in block coordinates.
+	SpawnBlocks []image.Point
 
 	// Our well-beloved hero Gopher
 	Gopher *MovingObj
@@ -33,12 +51,31 @@ type Model struct {
 	// The ancient enemies of Gopher: the bloodthirsty Bulldogs.
 	Bulldogs []*MovingObj
 
-	// Dead tells if Gopher is dead.
+	// Lives tells how many lives Gopher has left (including the current one).
+	Lives int
+
+	// Dead tells if Gopher is dead (has no more lives).
 	Dead bool
 
 	// Won tells if we won
 	Won bool
 
+	// HintsLeft tells how many hints may be requested in the current game.
+	HintsLeft int
+
+	// HintsUsed tells how many hints were used in the current game.
+	HintsUsed int
+
+	// Hint is the path from Gopher to the exit shown as a hint, in block coordinates.
+	// nil if no hint is to be shown.
+	Hint []image.Point
+
+	// HintUntil is the frame until the hint is to be shown.
+	HintUntil int
+
+	// Score of the game, calculated when the game is won.
+	Score int
+
 	// For Gopher we maintain multiple target positions which specify a path on which Gopher will move along
 	TargetPoss []image.Point
 }
@@ -68,6 +105,15 @@ type MovingObj struct {
 
 	// Target position this object is moving to
 	TargetPos image.Point
+
+	// Dist is the distance moved in pixels, used to animate walking.
+	Dist float64
+
+	// Idle is the number of consecutive frames the object has not moved.
+	Idle int
+
+	// v is the moving speed of the object in pixel/sec.
+	v float64
 }
 
 // steps steps the MovingObj.
@@ -76,7 +122,8 @@ func (m *MovingObj) step() {
 
 	// Only horizontal or vertical movement is allowed!
 	if x != m.TargetPos.X {
-		dx := math.Min(dt*v, math.Abs(float64(m.TargetPos.X)-m.Pos.X))
+		dx := math.Min(dt*m.v, math.Abs(float64(m.TargetPos.X)-m.Pos.X))
+		m.Dist += dx
 		if x > m.TargetPos.X {
 			dx = -dx
 			m.Dir = DirLeft
@@ -84,8 +131,10 @@ func (m *MovingObj) step() {
 			m.Dir = DirRight
 		}
 		m.Pos.X += dx
+		m.Idle = 0
 	} else if y != m.TargetPos.Y {
-		dy := math.Min(dt*v, math.Abs(float64(m.TargetPos.Y)-m.Pos.Y))
+		dy := math.Min(dt*m.v, math.Abs(float64(m.TargetPos.Y)-m.Pos.Y))
+		m.Dist += dy
 		if y > m.TargetPos.Y {
 			dy = -dy
 			m.Dir = DirUp
@@ -93,6 +142,9 @@ func (m *MovingObj) step() {
 			m.Dir = DirDown
 		}
 		m.Pos.Y += dy
+		m.Idle = 0
+	} else {
+		m.Idle++
 	}
 }
 
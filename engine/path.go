@@ -0,0 +1,112 @@
+package engine
+
+import "image"
+
+// BlockOf returns the block coordinates (X: column, Y: row) of the given pixel position.
+func BlockOf(x, y int) image.Point {
+	return image.Pt(x/BlockSize, y/BlockSize)
+}
+
+// BlockDist returns the distance of the given blocks in rows or columns, whichever is bigger.
+func BlockDist(a, b image.Point) int {
+	d := a.Sub(b)
+	if d.X < 0 {
+		d.X = -d.X
+	}
+	if d.Y < 0 {
+		d.Y = -d.Y
+	}
+	if d.Y > d.X {
+		return d.Y
+	}
+	return d.X
+}
+
+// BlockCenter returns the pixel position of the center of the given block.
+func BlockCenter(block image.Point) image.Point {
+	return image.Pt(block.X*BlockSize+BlockSize/2, block.Y*BlockSize+BlockSize/2)
+}
+
+// neighbours holds the block offsets of the neighbours of a block, indexed by Dir.
+var neighbours = [DirCount]image.Point{
+	DirRight: {1, 0},
+	DirLeft:  {-1, 0},
+	DirUp:    {0, -1},
+	DirDown:  {0, 1},
+}
+
+// FindPath returns the shortest path between the given blocks of the lab,
+// in block coordinates (X: column, Y: row), including both from and to.
+//
+// blocked may be used to mark additional blocks impassable besides walls, it may be nil.
+// nil is returned if there is no path.
+func FindPath(lab [][]Block, from, to image.Point, blocked func(block image.Point) bool) []image.Point {
+	if from == to {
+		return []image.Point{from}
+	}
+
+	rows, cols := len(lab), len(lab[0])
+	// prev holds the previous block on the shortest path for visited blocks, indexed by row*cols+col.
+	// -1 means not visited.
+	prev := make([]int, rows*cols)
+	for i := range prev {
+		prev[i] = -1
+	}
+	prev[from.Y*cols+from.X] = from.Y*cols + from.X
+
+	queue := []image.Point{from}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, d := range neighbours {
+			n := p.Add(d)
+			if n.X < 0 || n.Y < 0 || n.X >= cols || n.Y >= rows {
+				continue
+			}
+			idx := n.Y*cols + n.X
+			if prev[idx] >= 0 || lab[n.Y][n.X] == BlockWall {
+				continue
+			}
+			if blocked != nil && n != to && blocked(n) {
+				continue
+			}
+			prev[idx] = p.Y*cols + p.X
+			if n == to {
+				// Found it, reconstruct path:
+				var path []image.Point
+				for i := idx; ; i = prev[i] {
+					path = append(path, image.Pt(i%cols, i/cols))
+					if prev[i] == i {
+						break
+					}
+				}
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			queue = append(queue, n)
+		}
+	}
+
+	return nil
+}
+
+// Waypoints returns the blocks of the given path where the direction changes, and the last block.
+// These are the blocks which can be used as target positions to move along the path,
+// as target positions must be in the same row or column.
+func Waypoints(path []image.Point) []image.Point {
+	var wps []image.Point
+	for i := 1; i < len(path); i++ {
+		if i == len(path)-1 || path[i+1].Sub(path[i]) != path[i].Sub(path[i-1]) {
+			wps = append(wps, path[i])
+		}
+	}
+	return wps
+}
+
+// Distances returns the distances (in steps) of all blocks of the lab from the closest of
+// the given source blocks, indexed first by row, then by column. Unreachable blocks (and walls) have -1 distance.
+func Distances(lab [][]Block, sources ...image.Point) [][]int {
+	return analysisLab(lab, image.Point{}, image.Point{}).Distances(sources...)
+}
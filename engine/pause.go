@@ -0,0 +1,13 @@
+package engine
+
+// pauseCmd is the command to pause or resume the game.
+type pauseCmd struct {
+	paused bool
+}
+
+// SetPaused enqueues a command to pause or resume the game.
+// While paused, commands are still processed but the game does not advance
+// (so pausing does not affect replays).
+func (e *Engine) SetPaused(paused bool) {
+	e.cmdChan <- &pauseCmd{paused}
+}
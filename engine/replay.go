@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Replay is the recording of a game: its config (including the seed)
+// and the user input events along with the frames they were handled in.
+//
+// Since the game is deterministic for a given config and seed,
+// this is enough to reproduce a game.
+type Replay struct {
+	// Config of the recorded game. Seed is always filled.
+	Config GameConfig `json:"config"`
+
+	// Events of the game, ordered by frame.
+	Events []ReplayEvent `json:"events"`
+
+	// Frames is the number of frames recorded.
+	Frames int `json:"frames"`
+}
+
+// ReplayEvent is a user input event of a replay.
+// Exactly one of Click, Key and Hint is non-nil.
+type ReplayEvent struct {
+	// Frame in which the event was handled.
+	Frame int          `json:"frame"`
+	Click *Click       `json:"click,omitempty"`
+	Key   *Key         `json:"key,omitempty"`
+	Hint  *HintRequest `json:"hint,omitempty"`
+}
+
+// Encode writes the replay to the given writer in JSON format.
+func (r *Replay) Encode(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// DecodeReplay decodes and validates a replay from the given reader.
+func DecodeReplay(rd io.Reader) (*Replay, error) {
+	r := new(Replay)
+	if err := json.NewDecoder(rd).Decode(r); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case r.Config.Difficulty == nil:
+		return nil, errors.New("replay: missing difficulty")
+	case r.Config.LabSize == nil:
+		return nil, errors.New("replay: missing lab size")
+	case r.Config.Speed == nil:
+		return nil, errors.New("replay: missing speed")
+	case r.Config.Seed == 0:
+		return nil, errors.New("replay: missing seed")
+	}
+	for i, ev := range r.Events {
+		if n := countNonNil(ev.Click != nil, ev.Key != nil, ev.Hint != nil); n != 1 {
+			return nil, errors.New("replay: event must have exactly one of click, key and hint")
+		}
+		if i > 0 && ev.Frame < r.Events[i-1].Frame {
+			return nil, errors.New("replay: events are not ordered by frame")
+		}
+	}
+
+	return r, nil
+}
+
+// countNonNil returns the number of true values.
+func countNonNil(nonNils ...bool) (n int) {
+	for _, nonNil := range nonNils {
+		if nonNil {
+			n++
+		}
+	}
+	return
+}
@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// playTestGame plays a game with the given seed, sending some keys and clicks,
+// and returns the engine after the given number of steps.
+func playTestGame(seed int64, steps int) *Engine {
+	e := NewEngine(func() {})
+	e.NewGame(GameConfig{
+		Difficulty: Difficulties[DifficultyDefaultIdx],
+		LabSize:    LabSizes[1],
+		Speed:      Speeds[SpeedDefaultIdx],
+		Seed:       seed,
+	})
+	dirs := []Dir{DirRight, DirDown, DirLeft, DirUp}
+	for i := 0; i < steps; i++ {
+		switch {
+		case i%15 == 3:
+			e.SendKey(Key{DirKeys: map[Dir]bool{dirs[i/15%len(dirs)]: true}})
+		case i%40 == 20:
+			pos := e.Model.Gopher.Pos
+			e.SendClick(Click{X: int(pos.X) + 2*BlockSize, Y: int(pos.Y), Left: true})
+		}
+		e.Step()
+	}
+	return e
+}
+
+// checkSameGame checks if the games of the given engines are in the same state.
+func checkSameGame(t *testing.T, e, e2 *Engine) {
+	t.Helper()
+	m, m2 := e.Model, e2.Model
+	if m.Seed != m2.Seed || m.Frame != m2.Frame || m.Lives != m2.Lives || m.Dead != m2.Dead || m.Won != m2.Won {
+		t.Errorf("Expected seed, frame, lives, dead, won: %d, %d, %d, %v, %v, got: %d, %d, %d, %v, %v",
+			m.Seed, m.Frame, m.Lives, m.Dead, m.Won, m2.Seed, m2.Frame, m2.Lives, m2.Dead, m2.Won)
+	}
+	if !reflect.DeepEqual(m.Lab, m2.Lab) || m.ExitBlock != m2.ExitBlock {
+		t.Errorf("Expected the same lab")
+	}
+	if !reflect.DeepEqual(m.Gopher, m2.Gopher) {
+		t.Errorf("Expected Gopher %+v, got: %+v", m.Gopher, m2.Gopher)
+	}
+	if !reflect.DeepEqual(m.Bulldogs, m2.Bulldogs) {
+		t.Errorf("Expected the same Bulldogs")
+	}
+}
+
+func TestReplayDeterministic(t *testing.T) {
+	checkSameGame(t, playTestGame(7, 300), playTestGame(7, 300))
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	const steps = 300
+	e := playTestGame(3, steps)
+	r := e.Replay()
+	if r.Config.Seed != 3 || len(r.Events) == 0 || r.Frames != e.Model.Frame {
+		t.Fatalf("Unexpected replay: seed %d, %d events, %d frames", r.Config.Seed, len(r.Events), r.Frames)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := r.Encode(buf); err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	r2, err := DecodeReplay(buf)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if !reflect.DeepEqual(r, r2) {
+		t.Errorf("Expected %+v, got: %+v", r, r2)
+	}
+
+	e2 := NewEngine(func() {})
+	e2.PlayReplay(r2)
+	for i := 0; i < steps; i++ {
+		e2.Step()
+	}
+	checkSameGame(t, e, e2)
+
+	// Events from the user are ignored during playback:
+	e3 := NewEngine(func() {})
+	e3.PlayReplay(r2)
+	for i := 0; i < steps; i++ {
+		if i%10 == 5 {
+			e3.SendKey(Key{DirKeys: map[Dir]bool{DirDown: true}})
+		}
+		e3.Step()
+	}
+	checkSameGame(t, e, e3)
+}
+
+func TestDecodeReplayErrors(t *testing.T) {
+	cfg := `"config": {"difficulty": {"name": "D"}, "labSize": {"name": "L", "rows": 9, "cols": 9},` +
+		` "speed": {"name": "S", "loopDelay": "50ms"}, "seed": 1}`
+	cases := []struct {
+		name, input string
+	}{
+		{"invalid json", `{`},
+		{"missing seed", strings.Replace(`{`+cfg+`}`, `, "seed": 1`, ``, 1)},
+		{"missing difficulty", `{"config": {"seed": 1}}`},
+		{"empty event", `{` + cfg + `, "events": [{"frame": 1}]}`},
+		{"unordered events", `{` + cfg + `, "events": [{"frame": 2, "click": {"x": 1, "y": 1}}, {"frame": 1, "click": {"x": 1, "y": 1}}]}`},
+	}
+	for _, c := range cases {
+		if _, err := DecodeReplay(strings.NewReader(c.input)); err == nil {
+			t.Errorf("[%s] Expected error", c.name)
+		}
+	}
+
+	if _, err := DecodeReplay(strings.NewReader(`{` + cfg + `, "events": [{"frame": 1, "key": {"dirKeys": {"0": true}}}]}`)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
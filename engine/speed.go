@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -14,11 +16,54 @@ type Speed struct {
 	Default bool
 }
 
+// NewSpeed creates a new Speed.
+// loopDelay is the delay between game iterations, it must be in the range of 1ms..1s.
+func NewSpeed(name string, loopDelay time.Duration) (*Speed, error) {
+	if name == "" {
+		return nil, errors.New("speed name must not be empty")
+	}
+	if loopDelay < time.Millisecond || loopDelay > time.Second {
+		return nil, fmt.Errorf("speed %q: loop delay must be in range 1ms..1s, got %v", name, loopDelay)
+	}
+	return &Speed{Name: name, loopDelay: loopDelay}, nil
+}
+
 func (s *Speed) String() string {
 	fps := (time.Second + s.loopDelay/2) / s.loopDelay // add half to make it round up from half
 	return fmt.Sprintf("%s (%d FPS)", s.Name, fps)
 }
 
+// speedJSON is the JSON representation of Speed.
+type speedJSON struct {
+	Name      string `json:"name"`
+	LoopDelay string `json:"loopDelay"` // In the format accepted by time.ParseDuration(), e.g. "50ms"
+	Default   bool   `json:"default,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *Speed) MarshalJSON() ([]byte, error) {
+	return json.Marshal(speedJSON{Name: s.Name, LoopDelay: s.loopDelay.String(), Default: s.Default})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The decoded values are validated.
+func (s *Speed) UnmarshalJSON(data []byte) error {
+	var sj speedJSON
+	if err := unmarshalStrict(data, &sj); err != nil {
+		return err
+	}
+	loopDelay, err := time.ParseDuration(sj.LoopDelay)
+	if err != nil {
+		return fmt.Errorf("speed %q: invalid loop delay: %v", sj.Name, err)
+	}
+	s2, err := NewSpeed(sj.Name, loopDelay)
+	if err != nil {
+		return err
+	}
+	*s = *s2
+	s.Default = sj.Default
+	return nil
+}
+
 // Speeds is a slice of all, ordered speeds.
 var Speeds = []*Speed{
 	&Speed{Name: "Slow", loopDelay: 67 * time.Millisecond},                  // ~15 FPS
@@ -0,0 +1,223 @@
+// Package env is a gym-style reinforcement learning environment on top of the engine.
+//
+// An Env runs the game headless: Reset starts a new game and returns the first observation,
+// Step applies an action, advances the game by a fixed number of frames and returns
+// the new observation, the reward, whether the episode is done and additional info.
+//
+// Serve exposes an Env over a line based JSON protocol, so external
+// training scripts (in any language) can drive it through stdin / stdout.
+package env
+
+import (
+	"errors"
+	"image"
+
+	"github.com/icza/golab/engine"
+)
+
+// Action is an action of the agent.
+type Action int
+
+// Possible actions.
+const (
+	// ActionNone does nothing, Gopher continues its current move.
+	ActionNone Action = iota
+	ActionRight
+	ActionLeft
+	ActionUp
+	ActionDown
+
+	// ActionCount is not a valid action: just to tell how many actions there are
+	ActionCount
+)
+
+// Observation channels
+const (
+	ChannelWall = iota
+	ChannelBulldog
+	ChannelGopher
+	ChannelExit
+
+	// ChannelCount is not a valid channel: just to tell how many channels there are
+	ChannelCount
+)
+
+// Rewards
+const (
+	// RewardStep is the reward of each step (a small penalty to encourage hurrying).
+	RewardStep = -0.001
+	// RewardProgress is the reward of getting one block closer to the exit.
+	RewardProgress = 0.01
+	// RewardWin is the reward of reaching the exit.
+	RewardWin = 1
+	// RewardCaught is the reward of being caught by a Bulldog.
+	RewardCaught = -1
+)
+
+// Observation is a tensor-like representation of the state of the game.
+// Data holds Channels*Rows*Cols values, indexed by [channel][row][col]:
+// Data[(channel*Rows+row)*Cols+col]. Values are 0 or 1.
+type Observation struct {
+	Rows     int       `json:"rows"`
+	Cols     int       `json:"cols"`
+	Channels int       `json:"channels"`
+	Data     []float32 `json:"data"`
+}
+
+// Info holds additional info about the state of the episode.
+type Info struct {
+	Step     int  `json:"step"`     // Steps taken in the episode
+	Frame    int  `json:"frame"`    // Frames of the game
+	Lives    int  `json:"lives"`    // Lives left
+	Distance int  `json:"distance"` // Distance of Gopher from the exit in blocks
+	Won      bool `json:"won"`
+	Dead     bool `json:"dead"`
+	Timeout  bool `json:"timeout"` // Tells if the episode ended due to reaching the max steps
+}
+
+// Config is the configuration of an episode.
+type Config struct {
+	Difficulty *engine.Difficulty
+	LabSize    *engine.LabSize
+
+	// FrameSkip is the number of frames a step advances. Defaults to 4.
+	FrameSkip int
+
+	// MaxSteps is the max number of steps of an episode. Defaults to 10,000.
+	MaxSteps int
+}
+
+// Env is the environment. Its methods must not be called concurrently.
+// Independent Envs may be used concurrently.
+type Env struct {
+	eng *engine.Engine
+	cfg Config
+
+	step  int
+	lives int
+	dist  int
+
+	// exitDists holds the distances of blocks from the exit
+	exitDists [][]int
+}
+
+// New creates a new Env. Reset must be called before Step.
+func New() *Env {
+	return &Env{eng: engine.NewEngine(func() {})}
+}
+
+// Reset starts a new episode with the given seed and config, and returns the first observation.
+func (e *Env) Reset(seed int64, cfg Config) (*Observation, error) {
+	if cfg.Difficulty == nil || cfg.LabSize == nil {
+		return nil, errors.New("difficulty and lab size must be provided")
+	}
+	if cfg.FrameSkip <= 0 {
+		cfg.FrameSkip = 4
+	}
+	if cfg.MaxSteps <= 0 {
+		cfg.MaxSteps = 10000
+	}
+	e.cfg = cfg
+
+	e.eng.NewGame(engine.GameConfig{
+		Difficulty: cfg.Difficulty,
+		LabSize:    cfg.LabSize,
+		Speed:      engine.Speeds[0], // Irrelevant, game is run as fast as possible
+		Seed:       seed,
+	})
+	e.eng.Step() // Process the new game command
+
+	m := e.eng.Model
+	m.RLock()
+	defer m.RUnlock()
+
+	e.step = 0
+	e.lives = m.Lives
+	e.exitDists = engine.Distances(m.Lab, m.ExitBlock)
+	e.dist = e.gopherDist()
+
+	return e.observe(), nil
+}
+
+// Step applies the given action, advances the game by the configured frames,
+// and returns the new observation, the reward, whether the episode is done and additional info.
+func (e *Env) Step(a Action) (obs *Observation, reward float64, done bool, info Info) {
+	if a > ActionNone && a < ActionCount {
+		e.eng.SendKey(engine.Key{DirKeys: map[engine.Dir]bool{engine.Dir(a - 1): true}})
+	}
+
+	m := e.eng.Model
+	for i := 0; i < e.cfg.FrameSkip; i++ {
+		e.eng.Step()
+		if m.Won || m.Dead { // Safe to read without lock: we're the only goroutine using the engine
+			break
+		}
+	}
+	e.step++
+
+	m.RLock()
+	defer m.RUnlock()
+
+	reward = RewardStep
+	dist := e.gopherDist()
+	reward += float64(e.dist-dist) * RewardProgress
+	e.dist = dist
+	if m.Lives < e.lives {
+		reward += float64(e.lives-m.Lives) * RewardCaught
+		e.lives = m.Lives
+	}
+	if m.Won {
+		reward += RewardWin
+	}
+
+	info = Info{
+		Step:     e.step,
+		Frame:    m.Frame,
+		Lives:    m.Lives,
+		Distance: dist,
+		Won:      m.Won,
+		Dead:     m.Dead,
+		Timeout:  e.step >= e.cfg.MaxSteps && !m.Won && !m.Dead,
+	}
+	done = m.Won || m.Dead || info.Timeout
+
+	return e.observe(), reward, done, info
+}
+
+// gopherDist returns the distance of Gopher from the exit in blocks.
+// The model must be locked for reading.
+func (e *Env) gopherDist() int {
+	g := e.eng.Model.Gopher
+	b := engine.BlockOf(int(g.Pos.X), int(g.Pos.Y))
+	return e.exitDists[b.Y][b.X]
+}
+
+// observe returns the observation of the current state.
+// The model must be locked for reading.
+func (e *Env) observe() *Observation {
+	m := e.eng.Model
+	obs := &Observation{
+		Rows:     m.Rows,
+		Cols:     m.Cols,
+		Channels: ChannelCount,
+		Data:     make([]float32, ChannelCount*m.Rows*m.Cols),
+	}
+	set := func(channel int, b image.Point) {
+		obs.Data[(channel*m.Rows+b.Y)*m.Cols+b.X] = 1
+	}
+
+	for row := range m.Lab {
+		for col, block := range m.Lab[row] {
+			if block == engine.BlockWall {
+				set(ChannelWall, image.Pt(col, row))
+			}
+		}
+	}
+	for _, bd := range m.Bulldogs {
+		set(ChannelBulldog, engine.BlockOf(int(bd.Pos.X), int(bd.Pos.Y)))
+	}
+	set(ChannelGopher, engine.BlockOf(int(m.Gopher.Pos.X), int(m.Gopher.Pos.Y)))
+	set(ChannelExit, m.ExitBlock)
+
+	return obs
+}
@@ -0,0 +1,136 @@
+package env
+
+import (
+	"math"
+	"testing"
+
+	"github.com/icza/golab/engine"
+)
+
+// testDifficulty returns a difficulty without Bulldogs.
+func testDifficulty(t *testing.T) *engine.Difficulty {
+	d, err := engine.NewDifficulty("Test", 0, 1, 0, 0.75, 4, 1)
+	if err != nil {
+		t.Fatalf("Failed to create difficulty: %v", err)
+	}
+	return d
+}
+
+// countChannel returns the number of cells set in the given channel of the observation.
+func countChannel(obs *Observation, channel int) (count int) {
+	size := obs.Rows * obs.Cols
+	for _, v := range obs.Data[channel*size : (channel+1)*size] {
+		if v != 0 {
+			count++
+		}
+	}
+	return
+}
+
+// greedyAction returns the action moving Gopher towards the exit.
+func (e *Env) greedyAction() Action {
+	g := e.eng.Model.Gopher
+	b := engine.BlockOf(g.TargetPos.X, g.TargetPos.Y)
+	dists := []struct{ dx, dy int }{{1, 0}, {-1, 0}, {0, -1}, {0, 1}} // Indexed by engine.Dir
+	for dir, d := range dists {
+		if dist := e.exitDists[b.Y+d.dy][b.X+d.dx]; dist >= 0 && dist < e.exitDists[b.Y][b.X] {
+			return Action(dir + 1)
+		}
+	}
+	return ActionNone
+}
+
+func TestReset(t *testing.T) {
+	e := New()
+	if _, err := e.Reset(1, Config{}); err == nil {
+		t.Errorf("Expected error for missing difficulty and lab size")
+	}
+
+	obs, err := e.Reset(1, Config{Difficulty: testDifficulty(t), LabSize: engine.LabSizes[0]})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if obs.Channels != ChannelCount || len(obs.Data) != obs.Channels*obs.Rows*obs.Cols {
+		t.Errorf("Invalid observation size: %d*%d*%d, got: %d", obs.Channels, obs.Rows, obs.Cols, len(obs.Data))
+	}
+	if n := countChannel(obs, ChannelGopher); n != 1 {
+		t.Errorf("Expected 1 Gopher, got: %d", n)
+	}
+	if n := countChannel(obs, ChannelExit); n != 1 {
+		t.Errorf("Expected 1 exit, got: %d", n)
+	}
+	if n := countChannel(obs, ChannelBulldog); n != 0 {
+		t.Errorf("Expected no Bulldogs, got: %d", n)
+	}
+}
+
+func TestStepWin(t *testing.T) {
+	e := New()
+	if _, err := e.Reset(2, Config{Difficulty: testDifficulty(t), LabSize: engine.LabSizes[1], FrameSkip: 10}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Each step moves Gopher a block closer to the exit (some slack for the first step):
+	dist, maxSteps := e.dist, e.dist+2
+	for step := 1; ; step++ {
+		if step > maxSteps {
+			t.Fatalf("Gopher did not reach the exit")
+		}
+		_, reward, done, info := e.Step(e.greedyAction())
+		exp := RewardStep + float64(dist-info.Distance)*RewardProgress
+		if info.Won {
+			exp += RewardWin
+		}
+		if math.Abs(reward-exp) > 1e-9 {
+			t.Errorf("[step %d] Expected reward %v, got: %v", step, exp, reward)
+		}
+		if info.Step != step || info.Dead || info.Timeout {
+			t.Errorf("[step %d] Unexpected info: %+v", step, info)
+		}
+		dist = info.Distance
+		if done {
+			if !info.Won || info.Distance != 0 {
+				t.Errorf("Expected win, got: %+v", info)
+			}
+			break
+		}
+	}
+}
+
+func TestStepTimeout(t *testing.T) {
+	e := New()
+	if _, err := e.Reset(3, Config{Difficulty: testDifficulty(t), LabSize: engine.LabSizes[1], MaxSteps: 3}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for step := 1; step <= 3; step++ {
+		_, reward, done, info := e.Step(ActionNone)
+		// Reset processes the new game command in the first frame, then each step advances 4 frames:
+		if reward != RewardStep || done != (step == 3) || info.Timeout != (step == 3) || info.Frame != 1+step*4 {
+			t.Errorf("[step %d] Unexpected reward %v, done %v, info: %+v", step, reward, done, info)
+		}
+	}
+}
+
+func TestStepDead(t *testing.T) {
+	d, err := engine.NewDifficulty("Deadly", 100, 1, 1, 2, 0, 1)
+	if err != nil {
+		t.Fatalf("Failed to create difficulty: %v", err)
+	}
+	e := New()
+	if _, err := e.Reset(4, Config{Difficulty: d, LabSize: engine.LabSizes[0]}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for step := 1; ; step++ {
+		if step > 1000 {
+			t.Fatalf("Gopher was not caught")
+		}
+		_, reward, done, info := e.Step(ActionNone)
+		if !done {
+			continue
+		}
+		if !info.Dead || info.Lives != 0 || reward != RewardStep+RewardCaught {
+			t.Errorf("Expected dead with reward %v, got: %v, info: %+v", RewardStep+RewardCaught, reward, info)
+		}
+		break
+	}
+}
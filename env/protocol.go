@@ -0,0 +1,114 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/icza/golab/config"
+	"github.com/icza/golab/engine"
+)
+
+// Request is a request of the JSON protocol. Each request is a JSON object on its own line.
+//
+// Supported commands:
+//   - "reset": starts a new episode, uses Seed, Difficulty, LabSize, FrameSkip and MaxSteps
+//   - "step": takes a step, uses Action
+//   - "close": ends the session
+type Request struct {
+	Cmd string `json:"cmd"`
+
+	Seed       int64  `json:"seed,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"` // Name of a difficulty preset
+	LabSize    string `json:"labSize,omitempty"`    // Name of a lab size preset or WxH
+	FrameSkip  int    `json:"frameSkip,omitempty"`
+	MaxSteps   int    `json:"maxSteps,omitempty"`
+
+	Action Action `json:"action,omitempty"`
+}
+
+// Response is a response of the JSON protocol, written as a JSON object on its own line.
+type Response struct {
+	Observation *Observation `json:"observation,omitempty"`
+	Reward      float64      `json:"reward"`
+	Done        bool         `json:"done"`
+	Info        *Info        `json:"info,omitempty"`
+
+	// Error is the error message if the request failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Serve serves the JSON protocol: reads requests from r and writes responses to w,
+// until a "close" request is received or r is exhausted.
+func Serve(r io.Reader, w io.Writer) error {
+	dec := json.NewDecoder(r)
+	enc := json.NewEncoder(w)
+
+	env := New()
+
+	for {
+		var req Request
+		if err := dec.Decode(&req); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		var resp Response
+		switch req.Cmd {
+		case "reset":
+			cfg, err := configOf(&req)
+			if err != nil {
+				resp.Error = err.Error()
+				break
+			}
+			if resp.Observation, err = env.Reset(req.Seed, cfg); err != nil {
+				resp.Error = err.Error()
+			}
+		case "step":
+			if env.cfg.Difficulty == nil {
+				resp.Error = "reset must be called before step"
+				break
+			}
+			if req.Action < 0 || req.Action >= ActionCount {
+				resp.Error = fmt.Sprintf("invalid action: %d", req.Action)
+				break
+			}
+			var info Info
+			resp.Observation, resp.Reward, resp.Done, info = env.Step(req.Action)
+			resp.Info = &info
+		case "close":
+			return nil
+		default:
+			resp.Error = fmt.Sprintf("unknown cmd: %q", req.Cmd)
+		}
+
+		if err := enc.Encode(&resp); err != nil {
+			return err
+		}
+	}
+}
+
+// configOf returns the episode config specified by the request.
+// Unspecified presets use the default ones.
+func configOf(req *Request) (cfg Config, err error) {
+	cfg.Difficulty = engine.Difficulties[engine.DifficultyDefaultIdx]
+	if req.Difficulty != "" {
+		idx, err := config.FindDifficulty(req.Difficulty)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Difficulty = engine.Difficulties[idx]
+	}
+
+	cfg.LabSize = engine.LabSizes[engine.LabSizeDefaultIdx]
+	if req.LabSize != "" {
+		if cfg.LabSize, err = config.ParseLabSize(req.LabSize); err != nil {
+			return cfg, err
+		}
+	}
+
+	cfg.FrameSkip, cfg.MaxSteps = req.FrameSkip, req.MaxSteps
+	return cfg, nil
+}
@@ -17,10 +17,10 @@ func main() {
 	buf := &bytes.Buffer{}
 	buf.WriteString(`// This file is generated by go generate.
 
-package view
+package render
 
 // Embedded images mapped from image (file) name to file content encoded in Base64 format.
-// Whether these are used depends on the useEmbeddedImages const in images.go.
+// These are the images of the default theme.
 var base64Imgs = map[string]string{
 `)
 
@@ -0,0 +1,209 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+
+	"github.com/icza/golab/engine"
+	xdraw "golang.org/x/image/draw"
+)
+
+// AnimOptions holds options of rendering animations.
+type AnimOptions struct {
+	Options
+
+	// FrameStep tells to render every FrameStep-th simulation frame (frame skipping).
+	// Values less than 1 mean 1.
+	FrameStep int
+
+	// Scale of the rendered frames. 0 means 1.
+	Scale float64
+
+	// Viewport is the size of the Gopher-centred view in (unscaled) pixels
+	// to crop the frames to, like the game view does. Zero means the whole lab.
+	Viewport image.Point
+}
+
+// lastFrameDelay is the delay of the last frame of animations, so the end of the game can be seen.
+const lastFrameDelay = 2 * time.Second
+
+// Animation holds the rendered frames of an animation.
+type Animation struct {
+	opts AnimOptions
+
+	// Rendered frames, using the palette.Plan9 palette.
+	frames []*image.Paletted
+	// Delays of the frames.
+	delays []time.Duration
+
+	// Model frame of the last captured frame, -1 if no frame has been captured.
+	lastFrame int
+	// Tells if the game was over at the last captured frame.
+	lastOver bool
+
+	// Cached lab image of the game with the given counter.
+	labImg     *image.RGBA
+	labCounter int
+
+	// Cache of palette indices of colors.
+	colorIdxs map[color.RGBA]uint8
+}
+
+// NewAnimation returns a new, empty Animation.
+func NewAnimation(opts AnimOptions) *Animation {
+	if opts.FrameStep < 1 {
+		opts.FrameStep = 1
+	}
+	if opts.Scale <= 0 {
+		opts.Scale = 1
+	}
+	return &Animation{
+		opts:      opts,
+		lastFrame: -1,
+		colorIdxs: map[color.RGBA]uint8{},
+	}
+}
+
+// Len returns the number of frames in the animation.
+func (a *Animation) Len() int {
+	return len(a.frames)
+}
+
+// Capture renders the current state of the model as a new frame of the animation
+// if it is due according to the frame step. The state when the game gets over is always captured.
+// The model must be locked for reading.
+func (a *Animation) Capture(m *engine.Model) {
+	a.capture(m, false)
+}
+
+// Finish captures the final state of the model if it has not been captured yet,
+// and makes the last frame last longer so the end can be seen.
+// The model must be locked for reading.
+func (a *Animation) Finish(m *engine.Model) {
+	a.capture(m, true)
+	if len(a.delays) > 0 {
+		a.delays[len(a.delays)-1] = lastFrameDelay
+	}
+}
+
+// capture captures the current state of the model if it is due or forced,
+// and if it has not been captured yet.
+func (a *Animation) capture(m *engine.Model, force bool) {
+	over := m.Won || m.Dead
+	if m.Frame == a.lastFrame && over == a.lastOver {
+		return // Already captured
+	}
+	if over && a.lastOver {
+		return // Game over state already captured
+	}
+	if !force && !over && a.lastFrame >= 0 && m.Frame%a.opts.FrameStep != 0 {
+		return // Not due
+	}
+	a.lastFrame, a.lastOver = m.Frame, over
+
+	if a.labImg == nil || a.labCounter != m.Counter {
+		a.labImg = Lab(m, &a.opts.Options)
+		a.labCounter = m.Counter
+		if a.opts.Solution {
+			DrawPath(a.labImg, Solution(m), SolutionColor)
+		}
+	}
+
+	// Frame image in lab coordinates:
+	r := Viewport(m, a.opts.Viewport)
+	img := image.NewRGBA(r)
+	draw.Draw(img, r, a.labImg, r.Min, draw.Src)
+	if a.opts.Objects {
+		DrawObjects(img, m, &a.opts.Options)
+	}
+
+	var src image.Image = img
+	if a.opts.Scale != 1 {
+		scaled := image.NewRGBA(image.Rect(0, 0, int(float64(r.Dx())*a.opts.Scale+0.5), int(float64(r.Dy())*a.opts.Scale+0.5)))
+		xdraw.ApproxBiLinear.Scale(scaled, scaled.Bounds(), img, r, draw.Src, nil)
+		src = scaled
+	}
+
+	a.frames = append(a.frames, a.paletted(src))
+	a.delays = append(a.delays, engine.GameTime(a.opts.FrameStep))
+}
+
+// paletted converts the given image to a paletted image with zero Min point.
+func (a *Animation) paletted(src image.Image) *image.Paletted {
+	b := src.Bounds()
+	dst := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), palette.Plan9)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBAModel.Convert(src.At(x, y)).(color.RGBA)
+			idx, ok := a.colorIdxs[c]
+			if !ok {
+				idx = uint8(dst.Palette.Index(c))
+				a.colorIdxs[c] = idx
+			}
+			dst.SetColorIndex(x-b.Min.X, y-b.Min.Y, idx)
+		}
+	}
+	return dst
+}
+
+// EncodeGIF writes the animation as an animated GIF.
+func (a *Animation) EncodeGIF(w io.Writer) error {
+	g := &gif.GIF{Image: a.frames}
+	for _, d := range a.delays {
+		g.Delay = append(g.Delay, int(d/(10*time.Millisecond)))
+	}
+	return gif.EncodeAll(w, g)
+}
+
+// Viewport returns the rectangle of the lab (in lab coordinates) of the given size
+// centered on Gopher, corrected at the edges of the lab like the game view does.
+// If size is zero or bigger than the lab, the lab size is used.
+// The model must be locked for reading.
+func Viewport(m *engine.Model, size image.Point) image.Rectangle {
+	labSize := image.Pt(m.Cols*engine.BlockSize, m.Rows*engine.BlockSize)
+	if size.X <= 0 || size.X > labSize.X {
+		size.X = labSize.X
+	}
+	if size.Y <= 0 || size.Y > labSize.Y {
+		size.Y = labSize.Y
+	}
+
+	min := image.Pt(int(m.Gopher.Pos.X)-size.X/2, int(m.Gopher.Pos.Y)-size.Y/2)
+	if min.X < 0 {
+		min.X = 0
+	}
+	if min.Y < 0 {
+		min.Y = 0
+	}
+	if min.X+size.X > labSize.X {
+		min.X = labSize.X - size.X
+	}
+	if min.Y+size.Y > labSize.Y {
+		min.Y = labSize.Y - size.Y
+	}
+	return image.Rectangle{Min: min, Max: min.Add(size)}
+}
+
+// RenderReplay plays back the given replay headless and renders its animation.
+func RenderReplay(r *engine.Replay, opts AnimOptions) *Animation {
+	a := NewAnimation(opts)
+
+	eng := engine.NewEngine(func() {})
+	eng.PlayReplay(r)
+
+	m := eng.Model
+	eng.Step() // Process the command that started the game
+	a.Capture(m)
+	for m.Frame < r.Frames && !m.Won && !m.Dead {
+		eng.Step()
+		a.Capture(m)
+	}
+	a.Finish(m)
+
+	return a
+}
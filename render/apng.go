@@ -0,0 +1,128 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image/png"
+	"io"
+	"time"
+)
+
+// pngSignature is the signature of PNG files.
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// EncodeAPNG writes the animation as an animated PNG (APNG).
+//
+// Frames are encoded with image/png, and their image data chunks are
+// assembled into an APNG stream with the required animation control chunks.
+func (a *Animation) EncodeAPNG(w io.Writer) error {
+	if len(a.frames) == 0 {
+		return errors.New("no frames")
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(pngSignature)
+
+	// seq is the sequence number of fcTL and fdAT chunks
+	var seq uint32
+	buf := &bytes.Buffer{}
+	for i, frame := range a.frames {
+		buf.Reset()
+		if err := png.Encode(buf, frame); err != nil {
+			return err
+		}
+		chunks, err := pngChunks(buf.Bytes())
+		if err != nil {
+			return err
+		}
+
+		if i == 0 {
+			// Header chunks (IHDR, PLTE...) of the first frame are used for all frames.
+			for _, c := range chunks {
+				if c.typ == "IDAT" {
+					break
+				}
+				writeChunk(bw, c.typ, c.data)
+			}
+			actl := make([]byte, 8)
+			binary.BigEndian.PutUint32(actl, uint32(len(a.frames)))
+			// Number of plays is 0: loop forever.
+			writeChunk(bw, "acTL", actl)
+		}
+
+		b := frame.Bounds()
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl, seq)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(b.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(b.Dy()))
+		// x and y offsets are 0
+		binary.BigEndian.PutUint16(fctl[20:], uint16(a.delays[i]/time.Millisecond))
+		binary.BigEndian.PutUint16(fctl[22:], 1000)
+		// Dispose op and blend op are 0: none and source
+		writeChunk(bw, "fcTL", fctl)
+		seq++
+
+		for _, c := range chunks {
+			if c.typ != "IDAT" {
+				continue
+			}
+			if i == 0 {
+				writeChunk(bw, "IDAT", c.data)
+				continue
+			}
+			fdat := make([]byte, 4+len(c.data))
+			binary.BigEndian.PutUint32(fdat, seq)
+			copy(fdat[4:], c.data)
+			writeChunk(bw, "fdAT", fdat)
+			seq++
+		}
+	}
+
+	writeChunk(bw, "IEND", nil)
+	return bw.Flush()
+}
+
+// pngChunk is a chunk of a PNG file.
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+// pngChunks splits the given encoded PNG file into chunks.
+func pngChunks(data []byte) (chunks []pngChunk, err error) {
+	if !bytes.HasPrefix(data, []byte(pngSignature)) {
+		return nil, errors.New("invalid PNG signature")
+	}
+	data = data[len(pngSignature):]
+	for len(data) > 0 {
+		if len(data) < 12 {
+			return nil, errors.New("invalid PNG chunk")
+		}
+		size := binary.BigEndian.Uint32(data)
+		if uint32(len(data)-12) < size {
+			return nil, errors.New("invalid PNG chunk size")
+		}
+		chunks = append(chunks, pngChunk{typ: string(data[4:8]), data: data[8 : 8+size]})
+		data = data[12+size:]
+	}
+	return chunks, nil
+}
+
+// writeChunk writes a PNG chunk with the given type and data.
+func writeChunk(w io.Writer, typ string, data []byte) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(len(data)))
+	w.Write(b[:])
+
+	crc := crc32.NewIEEE()
+	io.WriteString(crc, typ)
+	crc.Write(data)
+
+	io.WriteString(w, typ)
+	w.Write(data)
+	binary.BigEndian.PutUint32(b[:], crc.Sum32())
+	w.Write(b[:])
+}
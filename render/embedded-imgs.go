@@ -1,9 +1,9 @@
 // This file is generated by go generate.
 
-package view
+package render
 
 // Embedded images mapped from image (file) name to file content encoded in Base64 format.
-// Whether these are used depends on the useEmbeddedImages const in images.go.
+// These are the images of the default theme.
 var base64Imgs = map[string]string{
 	"gopher-right.png":  "iVBORw0KGgoAAAANSUhEUgAAACgAAAAoCAYAAACM/rhtAAAG1ElEQVRYw7WYf1BU1xXHv/f92t+7LArLAjVQ0SICiwVK/dFITYxxtLXJ1FQkNZi2GWvSmNJpkxpbOzq2o2lTTUyamSoxmjQZEyfRdEIrGiM/HAwSMTRSQ5BdF5ZfC8vKsr/evnv7h7TWVmBZ3PPnO+ee93nnnnPueZcgRuElaW7R5soFolazUJM40ybpDVm8pLICAKPKqBIO9Ya8w+3BYU9j36cttV9Un6gHQKf6HjJV+7SSRQWZ99x/Kq98YyIvSaCKAjB2W9eU3tAJajXsp0+ibtevVnmvdVbPe/B7OvPsrwitb1QFR1xdwekA5i9+evubksFobDn48rbc8o0/mr9uw2LX5c+gdTkQpgpUhV9HosUK9l+QvFqNoeNH8XBBDjIzMnC6rh515lSYM7NQv2tb99ee/EUSJ4pSYHBw5PX7FhYCaJ8yoKjWlJZV155JmpeLSCgE+5kaJOTkodTtwNpFJdCZTAAAl9OJ31afBl26AoQxMEqxxNmG9SuW3+LP5XRiS8NFWIsX4dM3DoITRMx/6PvoaT7Pjq37lh6Af0pbuepPr13b1GpngkbLcss2sCc63Oz5E9Xsf0WWZcYYYz+srmWPf97LNhx6i1FFYbeT47X1bHNbN9vi8DB9Sip7ynmdPX6lh2UuX5kzHgg3HqDprgyOyjKYomDpb3ZDphSLkxNuMWpsbIQoijh8+DDS5AAgiMiP+EG4m24ppZgzZw7S09NRnJcLEALZ78d9f3wFSjiESCCAzprq4akC0g+3/vQFUWfA8j0vgFEKBkAkt5o3NTXBZDKhpqYGPAEIIfCEQrfYRCIROBwO2Gw2OLu7//NcnWCGq6kR7z/28AMAXON2i/EUvt6ehr5LzRcttgVlCZlZ4Hke6r4u5H8582YF5efDaDRix/btONYzBNFihUfSYlVaErixKPI8j8LCQhQV2PDu1S6w9AwAgM5ixVurS7d6rrYfmLCdTaT0XrOHS3c+t4UXJYBStPa68VWNAPNYgYiiiJzsbDxTdQR2fwjvlX8HHaf/jga3F4WZd4GLyAgFAjAmW3DkUhu4e1aDRuQbOcQRgEByNtS+OhGDMJHSPHtOtjrBDCUcBgAkzM3Gbm8Ynt+/iByDFi5/AKN5ReCzbThf8SBGevvhc3WBu3sh9voFRIa8YEoEkt4ALLkXNHCzUJmiIGVBcepYJ2ExASbnFVj+L+SihJnfXou+scVGvw+/HDmFPxzfhT63F3qtGqlJJhw59xoufPMxkHDotr4ZpTCmz9KpDEYSGrnOplokAABJb9DcvoQoCKUApQAhsLsGYXd50O0V4A5p4XD2A3J4grjg39usthaVkJi3mFFl0rOTqDQ4UrwZvxZ8EIYGUVBQgO8efBNJy8rByaHJVov6FCtiBgx6h0ej6eqiEsZwJID+vj4AgEoQwJNJAwgAgqjTxQ443NnhiercURS8NOhHQGvB+x81wVRUAqYoUZ1YvCjFDui+/I+BaAcebkYydGPz1BRGJEI4fmK/kzjoD3o9iJsQEHCETAvQ5+pCPIUQbloR9PsH+uOJxyVkzGaxAlqevD+3JDQ6Gs8AcktddRvTLGbblABzstIL22v29u7bv60xHFHiRkdBUFmx8s+fn9zbsnJpQWXUgDMTjZsSTXpcuNAGIqniBihIEg68fQaiwCMYlK1RTzOO7oGTOo3Ksf7nL1csKK94Wm9NjU8GMoadZRWL2h29R/965pPXAYSn+tM0Y8OHH7v11rS4ADJKcaA4+yHZP/p2rFWcZIgTHABwggCO55Om02ZmcJIU1z6oS04xxAyYNN9mRpyFCDwXM6AuOUkbb8CgZ8gfMyAviHw84WgkgrDP54oZMOj1BuMJGAkEIKjVl2IG7P743AAhJH6AoSBdvmd/x3Sq+Ir3mj1+WyzL7rM7n2XTARzoOn/OF5ceKIr44oP32r2Oq3Q6gBhs+2xHPABH+3vRsGfnjyf9kMkM/nninUO+HhdwB3OREwQ468+eBdB6R25Y9Smpj67/W/1BQXUHJhtC4L5ymR5dc++XJro0ijqCNy6SXFUtW8o/CQVD02PjePRcbEbmB/s9APqiWRNNI9Yc21/ZsvuJNbY0exOaWzsgZOWCcBwYje5OnFep4HV0wnVo39Wqb2jMq5cVabNmpSx7t6bp1UlnxskMfvezsmKB5+crlEFiEX/gxKFnqp6q7Czd8dyjiXOzC3lJNUszYybURhM4UQRjFLLfj8CgG/KoLyj7/ZftH5062/zKvt0A+t6x/ODSulWL8wc815fcqZxO2PvsI8N/ef4nXgDJ49jMBrACwFoAawCUAFCP53Drpgfq7i6e90g0L/8XnMnOZ2N7sdsAAAAASUVORK5CYII=",
 	"bulldog-right.png": "iVBORw0KGgoAAAANSUhEUgAAACgAAAAoCAYAAACM/rhtAAAITUlEQVRYw+2Ya4weVRnHf8+Zmfey72Xf7rvd3XZLu9BSsS2lH4QKDQGK2KIhjYBYMQSNhhAkckkAIYaoQRAC1hARCpbYgAHlHlA0gCiNpQgUupa09MpS2u793X3vczvHDzNbVtMIbRE/2Cc5mcnMmef8znP+z3NmBo7aUfs/tDt+cK28enmx8I+rOgsf1Vc+bbgHLjpuQacqnZWyvZtak5aUGs73awFDu4rn/uW6ex+u/U8Bf7Wi/YrpicoNHSk90wsCZuShHigcS7yKb782pAuPnrO2/5efKuAdv3gsmX/5uhWWV1rWk6pevLBdp5pa6OoyjJWFjoLBpMCrwgeDSrt2593z79l3zREBrv3G3GTW3e9oK6UajaYUktpq7+x0PKu1I0xkEqMDe3zTqH7NBO7SFvEW92Q9KXtCzxToyMNgGabPhNAAWrBaDDTB1OGdPao20nXGkuy+19753EO14ADginlntyyc8m7Hc2bZwFvr1zQAvnLp9amllcc6M2EplbaVyqiwM5NSc5ISnuoYd14odsEPAjvtSHdS6XRWfEJj8LUwtcWQUCBKSCUg0GAlQCkIXEN6moACXCARHZtNoX9cqDWETEvrXQ1ryo3yyCXzZ508c+C8qenKimagOysjMtC7P7G5HtiV7nTjlKkpM80RnRJBiUhnQoW5nKNJ2oKIASVYFigBMQbRQhgaLCWIDVggKQjd6ChAfVzITDMEnmBbBhQ0GsIzbwsrTzWYhqbpWZSsuQvtFSf1X1ZvlG7aPmIzv82YQi44MagFZyctdDFjrJYEiIKQaPZig7Li2RsBQ9QECAVCg/Lj+ykgCSgIA8FRBhFI5w2uL2CBbQNh5Pv0mQYsg+crHEfTqFSWxUvc58Asf+PlXbd2mf4bO3KCShkkIRBHAWIQFcMIIAZ0DAmg4z5eNCg2kI6uhS5oDxwHcCCwBNs2EET9wyrotODkDLoCyjaMV1MlO/I8y1+9cv5xOfPuFdO6JNLERJTEROcyCfJAik26LvE9HfefDGxBGEC9AXkNygJbDDTA1CI3lhMtCAIqHT0rKedVNTHWgvTAmbO6dJoWAwkDTkziCQQmGniiGXOQQmWicyueoAPE+sJA6AmBEvaXIPQhrENzDPw6EIAbgEpN+IegabNvLH+nPeF+dlE820EjE7qKOtKMtZWapDUxcZscQYmFGkduAj6MACxjyKWE3j2KbFpTcQ2NupC2IY/gG8inDSohaM9Qa6YfMIn29QcAm82gTYdYlvnX6hj4UBmDwlQQKwaYWMLw36JoHUQCsSYFGB+DJT2anf2CNoISYVqWgJy9q1bNbmCkfE4hH+YafmJo0C3cP2/VJlcBmN4fOY40l4tvnMhxVD6II943BsMjoGuTEkDHUY4jhHsQ4FgKJoBaFYbKhveGndER19kcGPu1thb7hVq6cPOqLWeeNuuukUvLZ6+eXdLHnTmm5i+Zu2rPGxHKc1cKW55YOfj+0P3FXJi1CrF2RMADdwj+1ge5pCHnCN0FyOXj4mrHMH4M2MKH2p1IGl8IKjA8CltLFq9Xu09tbVH9PcVUffaXrxmbc95l3n/atWz37w/lEf+nLU6YVbFvIyA6Gni8AeUwsfWEaS37lSmftX1Q0+1BMR9lnljguTBegWIqCjwicUJJlI0avNDgqszj1/++b8OB0e+77CO3VWXnWtvF92cmLaJsDUF0JHi3CUN1MdvGE89331laOiSLjk9mCg/vHFVu31CkqaAC4sJARXBrk4t3BIcGo8EPIZFre+xQ932l1Ni9iI+xQByJ9BRHL6hDf02V90vnWoCFd765Y8F9pUu2yPGnbxpNP71l2PJGqgY/gGIa9g2CPxzVNrwPm2hoGCfIphLrDhkwaAaLXCNR3UpO6AaCGmb7kKG3NuWXP39px6bJD33nka2vP5W48Otb6/nzNpYyL20rCamEpu4L4seA4YcTbXgw7KcefGabP3yogNY1izPTm3X3862tcQnxwavDjn4jfx2a8oernx/59sEe7H1nU/DMu42di+dOf7Lfz2zqK3FiWyIodrcZQUcyxBhCXxhp2n5VJ3/8zcf3bz3kCDZ165+TE/urNjTrsGsQ1g1k1u0yx1zwUQ6+91Rf9fIn9/7utMXHfnVmgaoGGi6Mj0OzJlSbMNiwXnnJ+dLLh/PuqcYbumbCuFR4QrWB2VXPPFtcdO7yVX/sbX5cR47due+9sj26cyAq2HYKrCxk2gxtaa17klVzWICtSbqseEur1GCwptzN7oybL7zt8fqhODrm+hdGR+2Oi3UiOdY3akgnwMmBSgkhHFsce6PnsACzyfqLyUKy5ofQN2LYa8148Iant759OM6Wrdmzfp/uWLTXzW2olk1UvH0IQ6N3jGp9WIC7h6VWDtQGp8Wg7Uz/F9f0ffdIPpKWrn6/rxw4PxusWZg6aBfCUEr7pKN0WIANk/ctpbajYE9ZGz4B2+EV19dC6y3tR0W67OHevvvs8mEBDku3CcWposCScConxC8MR2Czu9qrjmW5kgA7DcVsuOCJEx74zd3Lp6x6uffQ/Ks59nbHFm8BSfhse2D/9vjUnl+fP/XaIwG8cPWr4x8w44cNT5qSgp7puu3kqfXzz+guXzX7/uybazfWrI8NOCVjJJnARgmz2gxL5vid89oaF6z7yUWFI4Fctmb7n3bV2x41IaHVAsfMNsxt19KW94tfeGXl3I8N2HHKDXVU221hkNjt2041W0hubs1YL6ZUqnqkS73wnuFvbdvbfmVQT7yIcXolm31kSH1meffVz245ZGfhLcWTKre0n9V/64k9ZvdG9Un+/hh43djB7QtmHv2vd9SO2n/B/glzicxhchBqQgAAAABJRU5ErkJggg==",
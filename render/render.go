@@ -0,0 +1,160 @@
+// Package render draws the game model to images: to an image.RGBA using the sprites of the game,
+// and to scalable SVG (e.g. to print labyrinths).
+//
+// The package does not depend on gio, so it can be used without a window,
+// e.g. to export labyrinths from the command line.
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/icza/golab/engine"
+)
+
+// Options holds options of rendering.
+type Options struct {
+	// Sprites to draw with. nil means DefaultSprites.
+	Sprites *Sprites
+
+	// Objects tells if Gopher, the Bulldogs and the path markers are to be drawn.
+	// If false, only the labyrinth with the start and exit is drawn (e.g. to print it).
+	Objects bool
+
+	// Solution tells if the solution (shortest path from Gopher's block to the exit)
+	// is to be overlaid.
+	Solution bool
+}
+
+// SolutionColor is the color of the solution overlay.
+var SolutionColor = color.RGBA{R: 0x20, G: 0xa0, B: 0x20, A: 0x80}
+
+// sprites returns the sprites to use.
+func (o *Options) sprites() *Sprites {
+	if o == nil || o.Sprites == nil {
+		return DefaultSprites
+	}
+	return o.Sprites
+}
+
+// Lab draws the blocks of the labyrinth and the exit sign to a new image.
+// The image is of the size of the lab: BlockSize pixels per block.
+// The model must be locked for reading.
+func Lab(m *engine.Model, opts *Options) *image.RGBA {
+	return LabTile(m, opts, image.Rect(0, 0, m.Cols, m.Rows))
+}
+
+// LabTile draws the blocks of the given rectangle of the labyrinth (in block coordinates)
+// and the exit sign if it's inside, to a new image. The rectangle is clipped to the lab.
+// The top left corner of the image (which is always at (0, 0)) is the top left corner
+// of the rectangle's first block: BlockSize pixels per block.
+// The model must be locked for reading.
+func LabTile(m *engine.Model, opts *Options, blocks image.Rectangle) *image.RGBA {
+	s := opts.sprites()
+
+	blocks = blocks.Intersect(image.Rect(0, 0, m.Cols, m.Rows))
+	origin := blocks.Min.Mul(engine.BlockSize)
+
+	img := image.NewRGBA(image.Rectangle{Max: blocks.Size().Mul(engine.BlockSize)})
+	for row := blocks.Min.Y; row < blocks.Max.Y; row++ {
+		for col := blocks.Min.X; col < blocks.Max.X; col++ {
+			block := m.Lab[row][col]
+			draw.Draw(img, blockRect(image.Pt(col, row)).Sub(origin), s.Blocks[block], image.Point{}, draw.Over)
+		}
+	}
+
+	// Exit sign:
+	if m.ExitBlock.In(blocks) {
+		draw.Draw(img, blockRect(m.ExitBlock).Sub(origin), s.Exit, image.Point{}, draw.Over)
+	}
+
+	return img
+}
+
+// Image draws the model to a new image.
+// The image is of the size of the lab: BlockSize pixels per block.
+// The model must be locked for reading.
+func Image(m *engine.Model, opts Options) *image.RGBA {
+	img := Lab(m, &opts)
+
+	if opts.Solution {
+		DrawPath(img, Solution(m), SolutionColor)
+	}
+
+	if opts.Objects {
+		DrawObjects(img, m, &opts)
+	}
+
+	return img
+}
+
+// DrawObjects draws the hint, the path markers, Gopher and the Bulldogs of the model to the given image
+// which must be in lab coordinates. Won sign is drawn centered if the game is won.
+// The model must be locked for reading.
+func DrawObjects(img draw.Image, m *engine.Model, opts *Options) {
+	s := opts.sprites()
+
+	// Hint:
+	DrawPath(img, m.Hint, SolutionColor)
+
+	// Target position markers:
+	drawCentered(img, s.Marker, m.Gopher.TargetPos)
+	for _, tp := range m.TargetPoss {
+		drawCentered(img, s.Marker, tp)
+	}
+
+	// Gopher (death and win animations are not played, the final state is drawn):
+	switch {
+	case m.Dead:
+		drawObj(img, s.Dead, m.Gopher)
+	case m.Won:
+		drawObj(img, s.Gophers[m.Gopher.Dir], m.Gopher)
+	default:
+		drawObj(img, s.GopherImage(m, 0), m.Gopher)
+	}
+
+	// Bulldogs:
+	for _, bd := range m.Bulldogs {
+		drawObj(img, s.BulldogImage(bd), bd)
+	}
+
+	if m.Won {
+		drawCentered(img, s.Won, img.Bounds().Min.Add(img.Bounds().Max).Div(2))
+	}
+}
+
+// Solution returns the shortest path from Gopher's block to the exit, in block coordinates.
+// Returns nil if the exit is not reachable.
+// The model must be locked for reading.
+func Solution(m *engine.Model) []image.Point {
+	from := engine.BlockOf(int(m.Gopher.Pos.X), int(m.Gopher.Pos.Y))
+	return engine.FindPath(m.Lab, from, m.ExitBlock, nil)
+}
+
+// DrawPath fills the blocks of the given path (in block coordinates) with the given color
+// to the given image which must be in lab coordinates.
+func DrawPath(img draw.Image, path []image.Point, c color.Color) {
+	src := image.NewUniform(c)
+	for _, p := range path {
+		draw.Draw(img, blockRect(p), src, image.Point{}, draw.Over)
+	}
+}
+
+// blockRect returns the rectangle of the given block in lab coordinates.
+func blockRect(block image.Point) image.Rectangle {
+	min := block.Mul(engine.BlockSize)
+	return image.Rectangle{Min: min, Max: min.Add(image.Pt(engine.BlockSize, engine.BlockSize))}
+}
+
+// drawObj draws the given image of the given moving obj.
+func drawObj(dst draw.Image, src image.Image, obj *engine.MovingObj) {
+	drawCentered(dst, src, image.Pt(int(obj.Pos.X), int(obj.Pos.Y)))
+}
+
+// drawCentered draws the given image centered at the given point.
+func drawCentered(dst draw.Image, src image.Image, center image.Point) {
+	b := src.Bounds()
+	min := center.Sub(image.Pt(b.Dx()/2, b.Dy()/2))
+	draw.Draw(dst, b.Sub(b.Min).Add(min), src, b.Min, draw.Over)
+}
@@ -0,0 +1,109 @@
+package render
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/draw"
+	"image/png"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/icza/golab/engine"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// newTestModel returns the model of a small game with a fixed seed, after some frames were played.
+// Presets are not used so the golden files don't change when they are tuned.
+func newTestModel(t *testing.T) *engine.Model {
+	ls, err := engine.NewLabSize("Test", 15, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := engine.NewDifficulty("Test", 20, 1, 0, 0.75, 4, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := engine.NewEngine(func() {})
+	e.NewGame(engine.GameConfig{
+		Difficulty: d,
+		LabSize:    ls,
+		Speed:      engine.Speeds[engine.SpeedDefaultIdx],
+		Seed:       1,
+	})
+	for i := 0; i < 30; i++ {
+		e.Step()
+	}
+	return e.Model
+}
+
+var goldenCases = []struct {
+	name string
+	opts Options
+}{
+	{"lab", Options{}},
+	{"objects", Options{Objects: true}},
+	{"solution", Options{Objects: true, Solution: true}},
+}
+
+func TestImageGolden(t *testing.T) {
+	m := newTestModel(t)
+
+	for _, c := range goldenCases {
+		img := Image(m, c.opts)
+		name := filepath.Join("testdata", c.name+".png")
+		if *update {
+			buf := &bytes.Buffer{}
+			if err := png.Encode(buf, img); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(name, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		// Compare pixels, not the encoded bytes which may change with the png package.
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		golden, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("[%s] Failed to decode golden image: %v", c.name, err)
+		}
+		exp := image.NewRGBA(golden.Bounds())
+		draw.Draw(exp, exp.Bounds(), golden, golden.Bounds().Min, draw.Src)
+		if exp.Rect != img.Rect || !bytes.Equal(exp.Pix, img.Pix) {
+			t.Errorf("[%s] Image differs from %s (run with -update to update it)", c.name, name)
+		}
+	}
+}
+
+func TestSVGGolden(t *testing.T) {
+	m := newTestModel(t)
+
+	for _, c := range goldenCases {
+		buf := &bytes.Buffer{}
+		if err := SVG(buf, m, c.opts, 5); err != nil {
+			t.Fatalf("[%s] SVG failed: %v", c.name, err)
+		}
+		name := filepath.Join("testdata", c.name+".svg")
+		if *update {
+			if err := ioutil.WriteFile(name, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		exp, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(exp, buf.Bytes()) {
+			t.Errorf("[%s] SVG differs from %s (run with -update to update it)", c.name, name)
+		}
+	}
+}
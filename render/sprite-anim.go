@@ -0,0 +1,130 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"github.com/icza/golab/engine"
+)
+
+// SpriteAnim is a sprite animation: a sequence of frames.
+type SpriteAnim struct {
+	// Frames of the animation.
+	Frames []*image.RGBA
+
+	// Rate is the number of frames per unit of progress: per pixel moved for walk animations,
+	// per second for time driven animations.
+	Rate float64
+
+	// Loop tells if the animation is repeated. If false, the last frame remains after the animation ended.
+	Loop bool
+}
+
+// Frame returns the frame of the animation at the given progress
+// (distance moved in pixels or time in seconds, see Rate).
+func (a *SpriteAnim) Frame(progress float64) *image.RGBA {
+	i := int(progress * a.Rate)
+	if i < 0 {
+		i = 0
+	}
+	if a.Loop {
+		i %= len(a.Frames)
+	} else if i >= len(a.Frames) {
+		i = len(a.Frames) - 1
+	}
+	return a.Frames[i]
+}
+
+// GopherImage returns the image of Gopher to draw: the frame of the matching animation if the sprites have one,
+// else the static image. over is the time elapsed since the game ended, it drives the death and win animations.
+// The model must be locked for reading.
+func (s *Sprites) GopherImage(m *engine.Model, over time.Duration) *image.RGBA {
+	g := m.Gopher
+	switch {
+	case m.Dead:
+		if s.GopherDeath != nil {
+			return s.GopherDeath.Frame(over.Seconds())
+		}
+		return s.Dead
+	case m.Won:
+		if s.GopherWin != nil {
+			return s.GopherWin.Frame(over.Seconds())
+		}
+	case g.Idle > 0:
+		if a := s.GopherIdle[g.Dir]; a != nil {
+			return a.Frame(engine.GameTime(g.Idle).Seconds())
+		}
+	default:
+		if a := s.GopherWalk[g.Dir]; a != nil {
+			return a.Frame(g.Dist)
+		}
+	}
+	return s.Gophers[g.Dir]
+}
+
+// BulldogImage returns the image of the given Bulldog to draw: the frame of its walk animation
+// if the sprites have one, else the static image.
+func (s *Sprites) BulldogImage(bd *engine.MovingObj) *image.RGBA {
+	if a := s.BulldogWalk[bd.Dir]; a != nil && bd.Idle == 0 {
+		return a.Frame(bd.Dist)
+	}
+	return s.Bulldogs[bd.Dir]
+}
+
+// Parameters of the built-in animations.
+const (
+	// walkBobPx is how high (in pixels) objects bob while walking.
+	walkBobPx = 2
+	// winJumpPx is how high (in pixels) Gopher jumps when celebrating.
+	winJumpPx = 4
+	// animFPS is the frame rate of the built-in time driven animations.
+	animFPS = 8
+)
+
+// initDefaultAnims creates the built-in animations from the static images of the default sprites:
+// objects bob while walking, Gopher fades into its dead image when dying and jumps when winning.
+func initDefaultAnims(s *Sprites) {
+	walk := func(img *image.RGBA) *SpriteAnim {
+		frames := []*image.RGBA{img}
+		for _, dy := range []int{-walkBobPx / 2, -walkBobPx, -walkBobPx / 2} {
+			frames = append(frames, shifted(img, dy))
+		}
+		// One walk cycle per block:
+		return &SpriteAnim{Frames: frames, Rate: float64(len(frames)) / engine.BlockSize, Loop: true}
+	}
+	for dir := engine.Dir(0); dir < engine.DirCount; dir++ {
+		s.GopherWalk[dir] = walk(s.Gophers[dir])
+		s.BulldogWalk[dir] = walk(s.Bulldogs[dir])
+	}
+
+	death := &SpriteAnim{Rate: animFPS}
+	for i := 0; i <= 4; i++ {
+		death.Frames = append(death.Frames, blended(s.Gophers[engine.DirDown], s.Dead, uint8(255*i/4)))
+	}
+	s.GopherDeath = death
+
+	win := &SpriteAnim{Rate: animFPS, Loop: true}
+	for _, dy := range []int{0, -winJumpPx / 2, -winJumpPx, -winJumpPx / 2} {
+		win.Frames = append(win.Frames, shifted(s.Gophers[engine.DirDown], dy))
+	}
+	s.GopherWin = win
+}
+
+// shifted returns a copy of the image shifted vertically by dy pixels.
+func shifted(src *image.RGBA, dy int) *image.RGBA {
+	img := image.NewRGBA(src.Bounds())
+	draw.Draw(img, img.Bounds().Add(image.Pt(0, dy)), src, image.Point{}, draw.Src)
+	return img
+}
+
+// blended returns the image src2 drawn over src1 with the given alpha.
+func blended(src1, src2 *image.RGBA, alpha uint8) *image.RGBA {
+	img := image.NewRGBA(src1.Bounds())
+	mask := image.NewUniform(color.Alpha{A: 255 - alpha})
+	draw.DrawMask(img, img.Bounds(), src1, image.Point{}, mask, image.Point{}, draw.Over)
+	mask = image.NewUniform(color.Alpha{A: alpha})
+	draw.DrawMask(img, img.Bounds(), src2, image.Point{}, mask, image.Point{}, draw.Over)
+	return img
+}
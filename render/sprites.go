@@ -0,0 +1,113 @@
+package render
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/icza/golab/engine"
+)
+
+//go:generate go run _generate-embedded-imgs/main.go
+
+// Sprites holds the images used to draw the game.
+// Each image has zero Min point, and blocks and moving objects are of the size of a block.
+type Sprites struct {
+	// Gophers holds images of Gopher for each direction.
+	Gophers [engine.DirCount]*image.RGBA
+
+	// Dead is the Dead Gopher image.
+	Dead *image.RGBA
+
+	// Bulldogs holds images of a Bulldog for each direction.
+	Bulldogs [engine.DirCount]*image.RGBA
+
+	// Blocks holds images of labyrinth blocks for each type.
+	Blocks [engine.BlockCount]image.Image
+
+	// Marker is the image of the path marker.
+	Marker *image.RGBA
+
+	// Exit is the image of the exit sign.
+	Exit *image.RGBA
+
+	// Won is the image of the winning sign.
+	Won *image.RGBA
+
+	// Optional animations of Gopher and the Bulldogs, per direction where applicable.
+	// Static images are used where an animation is nil.
+
+	// GopherWalk and BulldogWalk are walk cycles, driven by the distance moved.
+	GopherWalk, BulldogWalk [engine.DirCount]*SpriteAnim
+	// GopherIdle is played while Gopher is standing still.
+	GopherIdle [engine.DirCount]*SpriteAnim
+	// GopherDeath is played when Gopher dies, GopherWin when Gopher reaches the exit.
+	GopherDeath, GopherWin *SpriteAnim
+}
+
+// DefaultSprites holds the built-in sprites, decoded from the embedded images.
+var DefaultSprites = &Sprites{}
+
+func init() {
+	s := DefaultSprites
+	for dir := engine.Dir(0); dir < engine.DirCount; dir++ {
+		// Load Gopher images
+		s.Gophers[dir] = loadImg(fmt.Sprintf("gopher-%s.png", dir), true)
+		// Load Bulldog images
+		s.Bulldogs[dir] = loadImg(fmt.Sprintf("bulldog-%s.png", dir), true)
+	}
+
+	s.Blocks[engine.BlockEmpty] = image.NewUniform(color.RGBA{A: 0xff})
+	s.Blocks[engine.BlockWall] = loadImg("wall.png", true)
+	s.Dead = loadImg("gopher-dead.png", true)
+	s.Exit = loadImg("door.png", true)
+
+	s.Marker = loadImg("marker.png", false)
+	s.Won = loadImg("won.png", false)
+
+	initDefaultAnims(s)
+}
+
+// loadImg loads an embedded PNG image, converts it to image.RGBA and makes sure image has zero Min point.
+// blockSize tells if the image must be of the size of a block (else panics).
+func loadImg(name string, blockSize bool) *image.RGBA {
+	data, err := base64.StdEncoding.DecodeString(base64Imgs[name])
+	if err != nil {
+		panic(err)
+	}
+	img, err := decodeImg(data)
+	if err == nil && blockSize {
+		err = checkBlockSize(img)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("%s: %v", name, err))
+	}
+	return img
+}
+
+// decodeImg decodes an image from the specified data which must be of PNG format,
+// converts it to image.RGBA and makes sure the result has zero Min point.
+func decodeImg(data []byte) (*image.RGBA, error) {
+	src, err := png.Decode(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	b := src.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(img, img.Bounds(), src, b.Min, draw.Src)
+
+	return img, nil
+}
+
+// checkBlockSize checks that the image is of the size of a block.
+func checkBlockSize(img image.Image) error {
+	if size := img.Bounds().Size(); size.X != engine.BlockSize || size.Y != engine.BlockSize {
+		return fmt.Errorf("invalid image size: %dx%d (must be %dx%d)", size.X, size.Y, engine.BlockSize, engine.BlockSize)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/icza/golab/engine"
+)
+
+// SVG colors.
+const (
+	svgWallColor     = "#000"
+	svgEmptyColor    = "#fff"
+	svgStartColor    = "#1e90ff"
+	svgExitColor     = "#d2691e"
+	svgSolutionColor = "#20a020"
+	svgGopherColor   = "#3cb4dc"
+	svgBulldogColor  = "#8b4513"
+	svgMarkerColor   = "#ffd700"
+)
+
+// SVG writes the model as a scalable SVG image to the given writer.
+// One block is one user unit, the document is sized to blockSizeMM millimeters per block
+// (suitable for printing). Sprites are not used, objects are drawn with simple shapes.
+// The model must be locked for reading.
+func SVG(w io.Writer, m *engine.Model, opts Options, blockSizeMM float64) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%gmm" height="%gmm" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		float64(m.Cols)*blockSizeMM, float64(m.Rows)*blockSizeMM, m.Cols, m.Rows)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="%s"/>`+"\n", m.Cols, m.Rows, svgEmptyColor)
+
+	// Walls: one rect for each horizontal run of walls to keep the output small.
+	fmt.Fprintf(bw, `<g fill="%s">`+"\n", svgWallColor)
+	for row := range m.Lab {
+		for col := 0; col < m.Cols; {
+			if m.Lab[row][col] != engine.BlockWall {
+				col++
+				continue
+			}
+			start := col
+			for col < m.Cols && m.Lab[row][col] == engine.BlockWall {
+				col++
+			}
+			fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="1"/>`+"\n", start, row, col-start)
+		}
+	}
+	bw.WriteString("</g>\n")
+
+	if opts.Solution {
+		if path := Solution(m); len(path) > 0 {
+			fmt.Fprintf(bw, `<polyline fill="none" stroke="%s" stroke-width="0.3" stroke-linecap="round" stroke-linejoin="round" points="`, svgSolutionColor)
+			for i, p := range path {
+				if i > 0 {
+					bw.WriteByte(' ')
+				}
+				fmt.Fprintf(bw, "%g,%g", float64(p.X)+0.5, float64(p.Y)+0.5)
+			}
+			bw.WriteString(`"/>` + "\n")
+		}
+	}
+
+	// Start and exit:
+	svgBlockLabel(bw, m.StartBlock, "S", svgStartColor)
+	svgBlockLabel(bw, m.ExitBlock, "E", svgExitColor)
+
+	if opts.Objects {
+		svgCircle := func(x, y, r float64, color string) {
+			fmt.Fprintf(bw, `<circle cx="%g" cy="%g" r="%g" fill="%s"/>`+"\n", x, y, r, color)
+		}
+		toBlocks := func(v float64) float64 { return v / engine.BlockSize }
+
+		svgCircle(toBlocks(float64(m.Gopher.TargetPos.X)), toBlocks(float64(m.Gopher.TargetPos.Y)), 0.15, svgMarkerColor)
+		for _, tp := range m.TargetPoss {
+			svgCircle(toBlocks(float64(tp.X)), toBlocks(float64(tp.Y)), 0.15, svgMarkerColor)
+		}
+		svgCircle(toBlocks(m.Gopher.Pos.X), toBlocks(m.Gopher.Pos.Y), 0.4, svgGopherColor)
+		for _, bd := range m.Bulldogs {
+			svgCircle(toBlocks(bd.Pos.X), toBlocks(bd.Pos.Y), 0.4, svgBulldogColor)
+		}
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// svgBlockLabel writes a colored block with the given label.
+func svgBlockLabel(w io.Writer, block image.Point, label, color string) {
+	fmt.Fprintf(w, `<rect x="%d" y="%d" width="1" height="1" fill="%s"/>`+"\n", block.X, block.Y, color)
+	fmt.Fprintf(w, `<text x="%g" y="%g" font-size="0.8" font-family="sans-serif" font-weight="bold" text-anchor="middle" fill="#fff">%s</text>`+"\n",
+		float64(block.X)+0.5, float64(block.Y)+0.78, label)
+}
@@ -0,0 +1,338 @@
+package render
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/icza/golab/engine"
+)
+
+// ThemeManifestName is the name of the manifest file of themes.
+const ThemeManifestName = "theme.json"
+
+// ThemeManifest describes the sprites of a theme, it maps sprites to PNG image files.
+// Paths are relative to the manifest. Sprites not listed are taken from the default sprites.
+//
+// Gophers, bulldogs, the dead Gopher, the wall, the floor and the exit must be of the size of a block,
+// the marker must not be larger than a block.
+type ThemeManifest struct {
+	// Name of the theme. If empty, the name of the theme directory or zip file is used.
+	Name string `json:"name,omitempty"`
+
+	// Gophers maps directions ("right", "left", "up", "down") to Gopher images.
+	Gophers map[string]string `json:"gophers,omitempty"`
+
+	// Bulldogs maps directions ("right", "left", "up", "down") to Bulldog images.
+	Bulldogs map[string]string `json:"bulldogs,omitempty"`
+
+	Dead   string `json:"dead,omitempty"`
+	Wall   string `json:"wall,omitempty"`
+	Floor  string `json:"floor,omitempty"`
+	Exit   string `json:"exit,omitempty"`
+	Marker string `json:"marker,omitempty"`
+	Won    string `json:"won,omitempty"`
+
+	// Animations from sprite sheets. Animations not listed are derived from the static images.
+	// Walk and idle sheets must have a row for each direction in the order of right, left, up, down.
+	GopherWalk  *SpriteSheet `json:"gopherWalk,omitempty"`
+	GopherIdle  *SpriteSheet `json:"gopherIdle,omitempty"`
+	BulldogWalk *SpriteSheet `json:"bulldogWalk,omitempty"`
+	GopherDeath *SpriteSheet `json:"gopherDeath,omitempty"` // Played once
+	GopherWin   *SpriteSheet `json:"gopherWin,omitempty"`
+}
+
+// SpriteSheet describes an animation in a sprite sheet: a PNG image holding frames of the size of a block,
+// laid out horizontally, one row per direction where applicable.
+type SpriteSheet struct {
+	// File is the sprite sheet image file.
+	File string `json:"file"`
+
+	// Frames is the number of frames (in a row). If 0, it's calculated from the width of the image.
+	Frames int `json:"frames,omitempty"`
+
+	// Rate is the number of frames per block moved for walk animations (default: Frames, one cycle per block),
+	// frames per second for others (default: 8).
+	Rate float64 `json:"rate,omitempty"`
+}
+
+// Theme is a named set of sprites.
+type Theme struct {
+	Name    string
+	Sprites *Sprites
+}
+
+// String returns the name of the theme.
+func (t *Theme) String() string {
+	return t.Name
+}
+
+// DefaultTheme is the theme of the built-in sprites.
+var DefaultTheme = &Theme{Name: "Default", Sprites: DefaultSprites}
+
+// LoadTheme loads a theme from the given directory or zip file holding a theme manifest.
+// In zip files the manifest may also be in a top level directory.
+func LoadTheme(name string) (*Theme, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	themeName := strings.TrimSuffix(filepath.Base(name), ".zip")
+
+	var t *Theme
+	if fi.IsDir() {
+		t, err = loadTheme(themeName, func(file string) ([]byte, error) {
+			return ioutil.ReadFile(filepath.Join(name, filepath.FromSlash(file)))
+		})
+	} else {
+		t, err = loadThemeZip(themeName, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: %v", name, err)
+	}
+	return t, nil
+}
+
+// LoadThemes loads the themes found in the given directory: its subdirectories and zip files.
+// Invalid themes are skipped, the returned error lists them.
+func LoadThemes(dir string) ([]*Theme, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var themes []*Theme
+	var errs []string
+	for _, fi := range fis {
+		if !fi.IsDir() && !strings.HasSuffix(strings.ToLower(fi.Name()), ".zip") {
+			continue
+		}
+		t, err := LoadTheme(filepath.Join(dir, fi.Name()))
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		themes = append(themes, t)
+	}
+
+	if len(errs) > 0 {
+		return themes, fmt.Errorf("invalid themes: %s", strings.Join(errs, "; "))
+	}
+	return themes, nil
+}
+
+// loadThemeZip loads a theme from the named zip file.
+func loadThemeZip(themeName, name string) (*Theme, error) {
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	// Find the manifest closest to the root:
+	files := map[string]*zip.File{}
+	root := ""
+	found := false
+	for _, f := range zr.File {
+		files[f.Name] = f
+		if path.Base(f.Name) != ThemeManifestName {
+			continue
+		}
+		if dir := path.Dir(f.Name); !found || len(dir) < len(root) {
+			root, found = dir, true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no %s found", ThemeManifestName)
+	}
+
+	return loadTheme(themeName, func(file string) ([]byte, error) {
+		f := files[path.Join(root, file)]
+		if f == nil {
+			return nil, fmt.Errorf("%s not found", file)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	})
+}
+
+// loadTheme loads a theme whose files are read with the given function.
+// themeName is used if the manifest does not name the theme.
+func loadTheme(themeName string, readFile func(file string) ([]byte, error)) (*Theme, error) {
+	data, err := readFile(ThemeManifestName)
+	if err != nil {
+		return nil, err
+	}
+	var tm ThemeManifest
+	if err := json.Unmarshal(data, &tm); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %v", err)
+	}
+	if tm.Name != "" {
+		themeName = tm.Name
+	}
+
+	// Start from a copy of the default sprites:
+	s := new(Sprites)
+	*s = *DefaultSprites
+
+	// load loads the given image file if not empty.
+	// blockSize tells if the image must be of the size of a block,
+	// else it must not be larger than a block if maxBlock is true.
+	load := func(dst **image.RGBA, file string, blockSize, maxBlock bool) {
+		if file == "" || err != nil {
+			return
+		}
+		var data []byte
+		if data, err = readFile(file); err != nil {
+			return
+		}
+		var img *image.RGBA
+		if img, err = decodeImg(data); err != nil {
+			err = fmt.Errorf("%s: %v", file, err)
+			return
+		}
+		size := img.Bounds().Size()
+		switch {
+		case blockSize:
+			err = checkBlockSize(img)
+		case maxBlock && (size.X > engine.BlockSize || size.Y > engine.BlockSize):
+			err = fmt.Errorf("image too large: %dx%d (max %dx%d)", size.X, size.Y, engine.BlockSize, engine.BlockSize)
+		}
+		if err != nil {
+			err = fmt.Errorf("%s: %v", file, err)
+			return
+		}
+		*dst = img
+	}
+
+	// loadDirs loads the images of the given map keyed by direction.
+	loadDirs := func(dst *[engine.DirCount]*image.RGBA, files map[string]string) {
+		for key, file := range files {
+			dir := engine.Dir(0)
+			for ; dir < engine.DirCount && dir.String() != key; dir++ {
+			}
+			if dir == engine.DirCount {
+				if err == nil {
+					err = fmt.Errorf("invalid direction: %q", key)
+				}
+				return
+			}
+			load(&dst[dir], file, true, false)
+		}
+	}
+
+	loadDirs(&s.Gophers, tm.Gophers)
+	loadDirs(&s.Bulldogs, tm.Bulldogs)
+	load(&s.Dead, tm.Dead, true, false)
+	load(&s.Exit, tm.Exit, true, false)
+	load(&s.Marker, tm.Marker, false, true)
+	load(&s.Won, tm.Won, false, false)
+
+	var wall, floor *image.RGBA
+	load(&wall, tm.Wall, true, false)
+	load(&floor, tm.Floor, true, false)
+	if wall != nil {
+		s.Blocks[engine.BlockWall] = wall
+	}
+	if floor != nil {
+		s.Blocks[engine.BlockEmpty] = floor
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Derive animations from the static images of the theme, then load the sprite sheets:
+	initDefaultAnims(s)
+
+	// loadSheet loads the animations of the given sprite sheet with the given number of rows.
+	// walk tells if it's a walk animation.
+	loadSheet := func(ss *SpriteSheet, rows int, walk, loop bool) []*SpriteAnim {
+		if ss == nil || err != nil {
+			return nil
+		}
+		var sheet *image.RGBA
+		load(&sheet, ss.File, false, false)
+		if err != nil {
+			return nil
+		}
+		var anims []*SpriteAnim
+		if anims, err = sheetAnims(sheet, ss, rows, walk, loop); err != nil {
+			err = fmt.Errorf("%s: %v", ss.File, err)
+		}
+		return anims
+	}
+
+	if anims := loadSheet(tm.GopherWalk, int(engine.DirCount), true, true); anims != nil {
+		copy(s.GopherWalk[:], anims)
+	}
+	if anims := loadSheet(tm.GopherIdle, int(engine.DirCount), false, true); anims != nil {
+		copy(s.GopherIdle[:], anims)
+	}
+	if anims := loadSheet(tm.BulldogWalk, int(engine.DirCount), true, true); anims != nil {
+		copy(s.BulldogWalk[:], anims)
+	}
+	if anims := loadSheet(tm.GopherDeath, 1, false, false); anims != nil {
+		s.GopherDeath = anims[0]
+	}
+	if anims := loadSheet(tm.GopherWin, 1, false, true); anims != nil {
+		s.GopherWin = anims[0]
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return &Theme{Name: themeName, Sprites: s}, nil
+}
+
+// sheetAnims cuts the given sprite sheet into animations, one for each row.
+// walk tells if they are walk animations (Rate is per block moved).
+func sheetAnims(sheet *image.RGBA, ss *SpriteSheet, rows int, walk, loop bool) ([]*SpriteAnim, error) {
+	size := sheet.Bounds().Size()
+	frames := ss.Frames
+	if frames == 0 {
+		frames = size.X / engine.BlockSize
+	}
+	if frames <= 0 || size.X != frames*engine.BlockSize || size.Y != rows*engine.BlockSize {
+		return nil, fmt.Errorf("invalid sprite sheet size: %dx%d (must be %dx%d for %d frames of %d rows)",
+			size.X, size.Y, frames*engine.BlockSize, rows*engine.BlockSize, frames, rows)
+	}
+
+	rate := ss.Rate
+	switch {
+	case rate < 0:
+		return nil, fmt.Errorf("invalid rate: %g", rate)
+	case walk && rate == 0:
+		rate = float64(frames) / engine.BlockSize
+	case walk:
+		rate /= engine.BlockSize
+	case rate == 0:
+		rate = animFPS
+	}
+
+	anims := make([]*SpriteAnim, rows)
+	for row := range anims {
+		a := &SpriteAnim{Rate: rate, Loop: loop}
+		for i := 0; i < frames; i++ {
+			r := image.Rect(i*engine.BlockSize, row*engine.BlockSize, (i+1)*engine.BlockSize, (row+1)*engine.BlockSize)
+			// SubImage shares pixels with the sheet, copy the frame to have zero Min point:
+			frame := image.NewRGBA(image.Rect(0, 0, engine.BlockSize, engine.BlockSize))
+			draw.Draw(frame, frame.Bounds(), sheet, r.Min, draw.Src)
+			a.Frames = append(a.Frames, frame)
+		}
+		anims[row] = a
+	}
+	return anims, nil
+}
@@ -0,0 +1,230 @@
+package view
+
+import (
+	"math"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/io/pointer"
+)
+
+const (
+	// zoomMin and zoomMax are the limits of the zoom of the lab view.
+	zoomMin, zoomMax = 0.25, 3
+
+	// zoomStep is the zoom factor of a single zoom in / out step.
+	zoomStep = 1.25
+
+	// cameraEaseTau is the time constant of camera easing:
+	// the remaining distance shrinks to about 37% in this time.
+	cameraEaseTau = 120 * time.Millisecond
+
+	// panStepPx is the distance in window pixels the camera is moved by a pan action.
+	panStepPx = 120
+)
+
+// camera tells which part of the lab is visible in the lab view, and at what zoom.
+//
+// By default the camera follows Gopher. Panning detaches the camera (free-pan mode),
+// and when it starts following Gopher again, it eases back smoothly.
+type camera struct {
+	// zoom is the current zoom, eased towards targetZoom.
+	zoom, targetZoom float32
+
+	// center is the center of the view in lab coordinates.
+	center f32.Point
+
+	// free tells if the camera is detached from Gopher.
+	free bool
+
+	// easing tells if the camera is easing back to Gopher.
+	easing bool
+
+	// lastUpdate is the time of the last update.
+	lastUpdate time.Time
+
+	// gameCounter of the game the camera was last updated for.
+	gameCounter int
+
+	// dragPos is the last position of the pointer dragging the view, in window coordinates.
+	dragging bool
+	dragPos  f32.Point
+
+	// touches holds the positions of the active touch pointers (in window coordinates).
+	touches map[pointer.ID]f32.Point
+	// pinchDist is the last distance between 2 touch pointers while pinching, 0 if not pinching.
+	pinchDist float32
+	// pinchCenter is the last center point of 2 touch pointers while pinching.
+	pinchCenter f32.Point
+}
+
+// newCamera returns a new camera following Gopher at zoom 1.
+func newCamera() camera {
+	return camera{
+		zoom:       1,
+		targetZoom: 1,
+		touches:    map[pointer.ID]f32.Point{},
+	}
+}
+
+// update updates the camera for a new frame: eases zoom, and follows Gopher (at the given position)
+// unless in free-pan mode. Returns the current zoom and center.
+func (c *camera) update(gameCounter int, gopher f32.Point, now time.Time) (zoom float32, center f32.Point) {
+	if c.gameCounter != gameCounter {
+		// New game: jump to Gopher
+		c.gameCounter = gameCounter
+		c.free, c.easing = false, false
+		c.center = gopher
+	}
+
+	// Easing factor based on the elapsed time, so easing speed does not depend on the frame rate:
+	k := float32(1)
+	if !c.lastUpdate.IsZero() {
+		k = float32(1 - math.Exp(-float64(now.Sub(c.lastUpdate))/float64(cameraEaseTau)))
+	}
+	c.lastUpdate = now
+
+	c.zoom += (c.targetZoom - c.zoom) * k
+	if abs32(c.targetZoom-c.zoom) < 0.001 {
+		c.zoom = c.targetZoom
+	}
+
+	if !c.free {
+		if c.easing {
+			c.center = c.center.Add(gopher.Sub(c.center).Mul(k))
+			if d := gopher.Sub(c.center); abs32(d.X) < 1 && abs32(d.Y) < 1 {
+				c.easing = false
+			}
+		}
+		if !c.easing {
+			c.center = gopher
+		}
+	}
+
+	return c.zoom, c.center
+}
+
+// zoomBy multiplies the target zoom by the given factor.
+func (c *camera) zoomBy(factor float32) {
+	c.targetZoom *= factor
+	if c.targetZoom < zoomMin {
+		c.targetZoom = zoomMin
+	}
+	if c.targetZoom > zoomMax {
+		c.targetZoom = zoomMax
+	}
+}
+
+// resetZoom resets the target zoom to 1.
+func (c *camera) resetZoom() {
+	c.targetZoom = 1
+}
+
+// pan moves the camera by the given distance in window pixels,
+// and detaches it from Gopher.
+func (c *camera) pan(d f32.Point) {
+	c.free, c.easing = true, false
+	c.center = c.center.Add(d.Mul(1 / c.zoom))
+}
+
+// follow makes the camera follow Gopher again, easing back to it.
+func (c *camera) follow() {
+	if c.free {
+		c.free, c.easing = false, true
+	}
+}
+
+// handlePointer handles pointer events related to the camera:
+// scrolling zooms, dragging with the middle button pans, and pinching with 2 fingers zooms.
+// Returns true if the event was consumed by the camera.
+func (c *camera) handlePointer(e pointer.Event) bool {
+	if e.Scroll.Y != 0 {
+		if e.Scroll.Y < 0 {
+			c.zoomBy(zoomStep)
+		} else {
+			c.zoomBy(1 / zoomStep)
+		}
+		return true
+	}
+
+	if e.Source == pointer.Touch {
+		return c.handleTouch(e)
+	}
+
+	switch e.Type {
+	case pointer.Press:
+		if e.Buttons&pointer.ButtonMiddle != 0 {
+			c.dragging, c.dragPos = true, e.Position
+			return true
+		}
+	case pointer.Move:
+		if c.dragging {
+			c.pan(c.dragPos.Sub(e.Position))
+			c.dragPos = e.Position
+			return true
+		}
+	case pointer.Release, pointer.Cancel:
+		if c.dragging {
+			c.dragging = false
+			return true
+		}
+	}
+	return false
+}
+
+// handleTouch handles touch events: pinching with 2 fingers zooms, dragging with 2 fingers pans.
+// Returns true if the event was consumed by the camera: events of the second finger
+// and events while pinching.
+func (c *camera) handleTouch(e pointer.Event) bool {
+	switch e.Type {
+	case pointer.Press:
+		c.touches[e.PointerID] = e.Position
+		if len(c.touches) == 2 {
+			c.pinchDist, c.pinchCenter = c.touchDist()
+		}
+		return len(c.touches) > 1
+	case pointer.Move:
+		if _, ok := c.touches[e.PointerID]; !ok {
+			return false
+		}
+		c.touches[e.PointerID] = e.Position
+		if len(c.touches) == 2 && c.pinchDist > 0 {
+			if d, center := c.touchDist(); d > 0 {
+				c.zoomBy(d / c.pinchDist)
+				c.zoom = c.targetZoom // Follow fingers without easing
+				c.pan(c.pinchCenter.Sub(center))
+				c.pinchDist, c.pinchCenter = d, center
+			}
+			return true
+		}
+	case pointer.Release, pointer.Cancel:
+		delete(c.touches, e.PointerID)
+		pinching := c.pinchDist > 0
+		if len(c.touches) < 2 {
+			c.pinchDist = 0
+		}
+		return pinching
+	}
+	return false
+}
+
+// touchDist returns the distance and the center point of the first 2 touch pointers.
+func (c *camera) touchDist() (dist float32, center f32.Point) {
+	var ps []f32.Point
+	for _, p := range c.touches {
+		ps = append(ps, p)
+	}
+	if len(ps) < 2 {
+		return 0, f32.Point{}
+	}
+	d := ps[0].Sub(ps[1])
+	return float32(math.Hypot(float64(d.X), float64(d.Y))), ps[0].Add(ps[1]).Mul(0.5)
+}
+
+// abs32 returns the absolute value of x.
+func abs32(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,83 @@
+package view
+
+import (
+	"testing"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/io/pointer"
+)
+
+func TestCameraUpdate(t *testing.T) {
+	c := newCamera()
+	now := time.Now()
+	gopher := f32.Point{X: 100, Y: 60}
+
+	zoom, center := c.update(1, gopher, now)
+	if zoom != 1 || center != gopher {
+		t.Errorf("Expected zoom 1 and center %v, got: %v, %v", gopher, zoom, center)
+	}
+
+	// Easing towards the target zoom, reaching it eventually:
+	c.zoomBy(zoomStep)
+	zoom, _ = c.update(1, gopher, now.Add(cameraEaseTau))
+	if zoom <= 1 || zoom >= zoomStep {
+		t.Errorf("Expected eased zoom between 1 and %v, got: %v", zoomStep, zoom)
+	}
+	zoom, _ = c.update(1, gopher, now.Add(100*cameraEaseTau))
+	if zoom != zoomStep {
+		t.Errorf("Expected zoom %v, got: %v", zoomStep, zoom)
+	}
+
+	// Free-pan mode, then new game jumps to Gopher:
+	c.pan(f32.Point{X: 50})
+	if _, center = c.update(1, gopher, now.Add(101*cameraEaseTau)); center == gopher {
+		t.Errorf("Expected camera detached from Gopher")
+	}
+	gopher2 := f32.Point{X: 20, Y: 20}
+	if _, center = c.update(2, gopher2, now.Add(102*cameraEaseTau)); center != gopher2 || c.free {
+		t.Errorf("Expected camera following Gopher at %v, got: %v (free: %v)", gopher2, center, c.free)
+	}
+
+	// Zoom limits:
+	for i := 0; i < 50; i++ {
+		c.zoomBy(1 / zoomStep)
+	}
+	if c.targetZoom != zoomMin {
+		t.Errorf("Expected zoom %v, got: %v", zoomMin, c.targetZoom)
+	}
+	for i := 0; i < 50; i++ {
+		c.zoomBy(zoomStep)
+	}
+	if c.targetZoom != zoomMax {
+		t.Errorf("Expected zoom %v, got: %v", zoomMax, c.targetZoom)
+	}
+}
+
+func TestCameraPinch(t *testing.T) {
+	c := newCamera()
+	c.update(1, f32.Point{X: 100, Y: 100}, time.Now())
+
+	touch := func(typ pointer.Type, id pointer.ID, x float32) bool {
+		return c.handlePointer(pointer.Event{Type: typ, Source: pointer.Touch, PointerID: id, Position: f32.Point{X: x, Y: 100}})
+	}
+
+	if touch(pointer.Press, 1, 100) {
+		t.Errorf("First finger must not be consumed")
+	}
+	if !touch(pointer.Press, 2, 200) {
+		t.Errorf("Second finger must be consumed")
+	}
+	// Spread the fingers to double their distance:
+	if !touch(pointer.Move, 2, 300) {
+		t.Errorf("Pinch must be consumed")
+	}
+	if c.zoom != 2 {
+		t.Errorf("Expected zoom 2, got: %v", c.zoom)
+	}
+	touch(pointer.Release, 2, 300)
+	touch(pointer.Release, 1, 100)
+	if len(c.touches) != 0 || c.pinchDist != 0 {
+		t.Errorf("Expected no touches, got: %v (pinch dist: %v)", c.touches, c.pinchDist)
+	}
+}
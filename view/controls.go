@@ -0,0 +1,161 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"gioui.org/f32"
+	"gioui.org/io/key"
+	"github.com/icza/golab/config"
+)
+
+// controlsScreen is the in-game screen listing the key bindings, where keys can be rebound.
+type controlsScreen struct {
+	// visible tells if the controls screen is shown.
+	visible bool
+
+	// selected is the index of the selected action in config.Actions.
+	selected int
+
+	// capturing tells if the next key combo is to be bound to the selected action.
+	capturing bool
+
+	// presetIdx is the index of the last applied preset in config.KeyPresets.
+	presetIdx int
+
+	// message is a status message, e.g. the reason of a rejected binding.
+	message string
+}
+
+// keyNames maps the names of the special keys of gio to the key names of config.
+var keyNames = map[string]string{
+	key.NameLeftArrow:      config.KeyLeft,
+	key.NameRightArrow:     config.KeyRight,
+	key.NameUpArrow:        config.KeyUp,
+	key.NameDownArrow:      config.KeyDown,
+	key.NameReturn:         config.KeyEnter,
+	key.NameEnter:          config.KeyEnter,
+	key.NameEscape:         config.KeyEscape,
+	key.NameTab:            config.KeyTab,
+	key.NameDeleteBackward: config.KeyBackspace,
+	key.NameDeleteForward:  config.KeyDelete,
+	key.NameHome:           config.KeyHome,
+	key.NameEnd:            config.KeyEnd,
+	key.NamePageUp:         config.KeyPageUp,
+	key.NamePageDown:       config.KeyPageDown,
+	"Space":                config.KeySpace,
+}
+
+// keyCombo returns the key combo of the given key event.
+func keyCombo(e key.Event) config.KeyCombo {
+	kc := config.KeyCombo{
+		Ctrl:  e.Modifiers&key.ModCtrl != 0,
+		Alt:   e.Modifiers&key.ModAlt != 0,
+		Shift: e.Modifiers&key.ModShift != 0,
+		Key:   strings.ToUpper(e.Name),
+	}
+	if name, ok := keyNames[e.Name]; ok {
+		kc.Key = name
+	}
+	return kc.Normalize()
+}
+
+// handleControlsKey handles a key event while the controls screen is visible.
+//
+// Up and down select an action, Enter rebinds it (the next key combo is bound to it),
+// Backspace restores its default, P cycles the presets, S saves the key bindings,
+// and Escape (or the key of the controls action) closes the screen.
+func (v *View) handleControlsKey(e key.Event) {
+	cs := &v.controls
+	kc := keyCombo(e)
+
+	if cs.capturing {
+		cs.capturing = false
+		if e.Name == key.NameEscape {
+			cs.message = "Cancelled."
+			return
+		}
+		action := config.Actions[cs.selected]
+		if err := v.keyBindings.Bind(action, kc); err != nil {
+			cs.message = err.Error()
+			return
+		}
+		v.keys = v.keyBindings.Lookup()
+		cs.message = fmt.Sprintf("%s is bound to %s.", kc, action)
+		return
+	}
+
+	if v.keys[kc] == config.ActionControls {
+		cs.visible = false
+		return
+	}
+
+	switch e.Name {
+	case key.NameEscape:
+		cs.visible = false
+	case key.NameUpArrow:
+		cs.selected = (cs.selected + len(config.Actions) - 1) % len(config.Actions)
+	case key.NameDownArrow:
+		cs.selected = (cs.selected + 1) % len(config.Actions)
+	case key.NameReturn, key.NameEnter:
+		cs.capturing = true
+		cs.message = fmt.Sprintf("Press the new key for %s (Escape cancels)...", config.Actions[cs.selected])
+	case key.NameDeleteBackward:
+		action := config.Actions[cs.selected]
+		kb := v.keyBindings.Copy()
+		kb[action] = config.DefaultKeyBindings()[action]
+		if err := kb.Validate(); err != nil {
+			cs.message = err.Error()
+			return
+		}
+		v.SetKeyBindings(kb)
+		cs.message = fmt.Sprintf("%s is reset to its default.", action)
+	case "P":
+		cs.presetIdx = (cs.presetIdx + 1) % len(config.KeyPresets)
+		name := config.KeyPresets[cs.presetIdx]
+		kb, err := config.PresetKeyBindings(name)
+		if err != nil {
+			cs.message = err.Error()
+			return
+		}
+		v.SetKeyBindings(kb)
+		cs.message = fmt.Sprintf("Applied the %q preset.", name)
+	case "S":
+		if v.saveKeyBindings == nil {
+			cs.message = "Saving is not supported."
+			return
+		}
+		if err := v.saveKeyBindings(v.keyBindings.Copy()); err != nil {
+			cs.message = fmt.Sprintf("Failed to save: %v", err)
+			return
+		}
+		cs.message = "Key bindings saved."
+	}
+}
+
+// drawControlsScreen draws the controls screen if it is visible.
+func (v *View) drawControlsScreen() {
+	cs := &v.controls
+	if !cs.visible {
+		return
+	}
+
+	lines := []string{
+		"Controls   [Up/Down] select  [Enter] rebind  [Backspace] default",
+		"           [P] preset  [S] save  [Esc] close",
+		"",
+	}
+	for i, action := range config.Actions {
+		marker := "   "
+		if i == cs.selected {
+			marker = ">> "
+		}
+		lines = append(lines, fmt.Sprintf("%s%-12s %s", marker, action, strings.Join(v.keyBindings[action], ", ")))
+	}
+	if cs.message != "" {
+		lines = append(lines, "", cs.message)
+	}
+
+	pos := v.labViewRect().Min.Add(f32.Point{X: 10, Y: 10})
+	v.drawTextBox(pos, lines...)
+}
@@ -0,0 +1,35 @@
+package view
+
+import (
+	"testing"
+
+	"gioui.org/io/key"
+	"github.com/icza/golab/config"
+)
+
+func TestKeyCombo(t *testing.T) {
+	cases := []struct {
+		e   key.Event
+		exp string
+	}{
+		{key.Event{Name: key.NameReturn}, "Enter"},
+		{key.Event{Name: key.NameEnter, Modifiers: key.ModCtrl}, "Ctrl+Enter"},
+		{key.Event{Name: key.NameTab, Modifiers: key.ModShift}, "Shift+Tab"},
+		{key.Event{Name: key.NameDeleteForward}, "Delete"},
+		{key.Event{Name: key.NamePageDown}, "PageDown"},
+		{key.Event{Name: key.NameLeftArrow, Modifiers: key.ModShift}, "Shift+Left"},
+		{key.Event{Name: "+", Modifiers: key.ModShift}, "+"},
+		{key.Event{Name: "N", Modifiers: key.ModAlt}, "Alt+N"},
+		{key.Event{Name: "F1"}, "F1"},
+	}
+	for _, c := range cases {
+		kc := keyCombo(c.e)
+		if kc.String() != c.exp {
+			t.Errorf("[%q] Expected %q, got: %q", c.e.Name, c.exp, kc)
+		}
+		// Must match the binding of the same name:
+		if exp, err := config.ParseKeyCombo(c.exp); err != nil || exp != kc {
+			t.Errorf("[%q] Expected %+v, got: %+v (%v)", c.e.Name, exp, kc, err)
+		}
+	}
+}
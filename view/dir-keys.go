@@ -0,0 +1,67 @@
+package view
+
+import (
+	"time"
+
+	"github.com/icza/golab/engine"
+)
+
+// The gio version in use only reports key presses, so holding a key down is detected
+// from the auto-repeated key events, and key releases are synthesized when they stop.
+const (
+	// keyRepeatDelay is the max time between a key press and its first auto-repeated event.
+	keyRepeatDelay = 700 * time.Millisecond
+
+	// keyReleaseDelay is the time without auto-repeated events after which held keys are released.
+	keyReleaseDelay = 200 * time.Millisecond
+)
+
+// dirKeys tracks the state of the direction keys.
+type dirKeys struct {
+	// last is the direction of the last direction key event, received at lastTime
+	last     engine.Dir
+	lastTime time.Time
+
+	// held tells the directions reported to the engine as held down
+	held [engine.DirCount]bool
+}
+
+// handleDirKey handles a press of a direction key.
+//
+// The first press moves Gopher one block, its first auto-repeat tells the engine
+// that the key is held down (further auto-repeats are ignored).
+func (v *View) handleDirKey(dir engine.Dir, now time.Time) {
+	dk := &v.dirKeys
+	repeat := dir == dk.last && !dk.lastTime.IsZero() && now.Sub(dk.lastTime) < keyRepeatDelay
+	dk.last, dk.lastTime = dir, now
+
+	switch {
+	case dk.held[dir]:
+		// Auto-repeat of a held key, engine already knows
+	case repeat:
+		dk.held[dir] = true
+		v.engine.SendKey(engine.Key{DirKeys: map[engine.Dir]bool{dir: true}, Hold: true})
+	default:
+		v.engine.SendKey(engine.Key{DirKeys: map[engine.Dir]bool{dir: true}})
+	}
+}
+
+// checkKeyRelease releases the held direction keys if their auto-repeated events stopped.
+func (v *View) checkKeyRelease(now time.Time) {
+	dk := &v.dirKeys
+	if now.Sub(dk.lastTime) < keyReleaseDelay {
+		return
+	}
+
+	released := map[engine.Dir]bool{}
+	for dir, held := range dk.held {
+		if held {
+			released[engine.Dir(dir)] = true
+		}
+	}
+	if len(released) == 0 {
+		return
+	}
+	dk.held = [engine.DirCount]bool{}
+	v.engine.SendKey(engine.Key{DirKeys: released, Released: true})
+}
@@ -0,0 +1,42 @@
+package view
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"os"
+
+	"github.com/icza/golab/render"
+)
+
+// exportAnim renders the current game up to the current frame to an animated GIF
+// in the working directory. Rendering is done in the background from the replay of the game.
+func (v *View) exportAnim() {
+	r := v.engine.Replay()
+	// Use the size of the currently visible part of the lab:
+	viewport := image.Pt(int(v.labViewClip.Dx()), int(v.labViewClip.Dy()))
+	sprites := v.sprites
+
+	go func() {
+		a := render.RenderReplay(r, render.AnimOptions{
+			Options:   render.Options{Sprites: sprites, Objects: true},
+			FrameStep: 2,
+			Scale:     0.5,
+			Viewport:  viewport,
+		})
+
+		name := fmt.Sprintf("golab-%d-%d.gif", r.Config.Seed, r.Frames)
+		f, err := os.Create(name)
+		if err != nil {
+			log.Printf("Failed to save animation: %v", err)
+			return
+		}
+		defer f.Close()
+
+		if err := a.EncodeGIF(f); err != nil {
+			log.Printf("Failed to save animation: %v", err)
+			return
+		}
+		log.Printf("Animation saved to %s", name)
+	}()
+}
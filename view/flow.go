@@ -0,0 +1,84 @@
+package view
+
+import (
+	"image"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op"
+)
+
+// flowItem is a recorded widget of a flow layout.
+type flowItem struct {
+	macro op.MacroOp
+	size  image.Point
+}
+
+// flow lays out the given widgets left to right in rows like words of a text,
+// wrapping to a new row when the next widget does not fit into the max width.
+// Rows are centered horizontally, widgets are centered vertically in their rows.
+func flow(gtx *layout.Context, children ...layout.Widget) {
+	cs := gtx.Constraints
+	maxWidth := cs.Width.Max
+
+	// Record children to learn their sizes:
+	items := make([]flowItem, len(children))
+	for i, child := range children {
+		gtx.Constraints = layout.Constraints{
+			Width:  layout.Constraint{Max: cs.Width.Max},
+			Height: layout.Constraint{Max: cs.Height.Max},
+		}
+		items[i].macro.Record(gtx.Ops)
+		child()
+		items[i].macro.Stop()
+		items[i].size = gtx.Dimensions.Size
+	}
+	gtx.Constraints = cs
+
+	// Break into rows:
+	var rows [][]flowItem
+	rowWidth := 0
+	for i, item := range items {
+		if i == 0 || rowWidth+item.size.X > maxWidth {
+			rows = append(rows, nil)
+			rowWidth = 0
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], item)
+		rowWidth += item.size.X
+	}
+
+	// Place rows:
+	var size image.Point
+	for _, row := range rows {
+		rowSize := image.Point{}
+		for _, item := range row {
+			rowSize.X += item.size.X
+			if item.size.Y > rowSize.Y {
+				rowSize.Y = item.size.Y
+			}
+		}
+
+		x := (maxWidth - rowSize.X) / 2
+		if x < 0 {
+			x = 0
+		}
+		for _, item := range row {
+			var stack op.StackOp
+			stack.Push(gtx.Ops)
+			op.TransformOp{}.Offset(f32.Point{
+				X: float32(x),
+				Y: float32(size.Y + (rowSize.Y-item.size.Y)/2),
+			}).Add(gtx.Ops)
+			item.macro.Add()
+			stack.Pop()
+			x += item.size.X
+		}
+
+		size.Y += rowSize.Y
+		if rowSize.X > size.X {
+			size.X = rowSize.X
+		}
+	}
+
+	gtx.Dimensions = layout.Dimensions{Size: size}
+}
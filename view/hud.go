@@ -0,0 +1,138 @@
+package view
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"github.com/icza/golab/config"
+	"github.com/icza/golab/engine"
+)
+
+// nearbyBlocks is the distance in blocks within which Bulldogs are reported as nearby on the HUD.
+const nearbyBlocks = 5
+
+// Colors of the HUD.
+var (
+	hudTextColor = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	hudWarnColor = color.RGBA{R: 255, G: 90, B: 70, A: 255}
+	hudWinColor  = color.RGBA{R: 110, G: 230, B: 110, A: 255}
+)
+
+// hudItem is a text item of the HUD.
+type hudItem struct {
+	text  string
+	color color.RGBA
+}
+
+// drawHUD draws the heads-up display: the game time, lives, queued waypoints, Bulldogs and Bulldogs nearby
+// in the bottom left corner of the lab view, and a banner telling the outcome when the game is over.
+func (v *View) drawHUD() {
+	m := v.engine.Model
+	m.RLock()
+	frame, lives, dead, won := m.Frame, m.Lives, m.Dead, m.Won
+	waypoints, capacity := len(m.TargetPoss), cap(m.TargetPoss)
+	bulldogs, nearby := len(m.Bulldogs), bulldogsNearby(m)
+	score, hintsUsed := m.Score, m.HintsUsed
+	highScore := won && v.scoreCounter == m.Counter && v.scoreRank >= 0
+	m.RUnlock()
+
+	items := []hudItem{
+		{fmt.Sprintf("Time: %s", formatGameTime(engine.GameTime(frame))), hudTextColor},
+		{fmt.Sprintf("Lives: %d", lives), hudTextColor},
+		{fmt.Sprintf("Waypoints: %d/%d", waypoints, capacity), hudTextColor},
+		{fmt.Sprintf("Bulldogs: %d", bulldogs), hudTextColor},
+	}
+	if waypoints == capacity {
+		items[2].color = hudWarnColor
+	}
+	if lives == 1 && !dead {
+		items[1].color = hudWarnColor
+	}
+	if nearby > 0 && !dead && !won {
+		items = append(items, hudItem{fmt.Sprintf("Nearby: %d!", nearby), hudWarnColor})
+	}
+
+	r := v.labViewRect()
+	v.drawHUDItems(f32.Point{X: r.Min.X + 10, Y: r.Max.Y - 10}, items)
+
+	center := r.Min.Add(r.Max).Mul(0.5)
+	switch {
+	case dead:
+		hint := ""
+		if keys := v.keyBindings[config.ActionNewGame]; len(keys) > 0 {
+			hint = fmt.Sprintf(" Press %s for a new game.", keys[0])
+		}
+		v.drawBanner(center, f32.Point{X: 0.5, Y: 0.5}, hudWarnColor, "Gopher was caught by a Bulldog!", "No lives left."+hint)
+	case won:
+		// Place the banner below the winning sign:
+		center.Y += float32(v.imgOpWon.src.Bounds().Dy())/2 + 10
+		text := fmt.Sprintf("Score: %d   Hints used: %d", score, hintsUsed)
+		if highScore {
+			text += fmt.Sprintf("   New high score: #%d!", v.scoreRank+1)
+		}
+		v.drawBanner(center, f32.Point{X: 0.5}, hudWinColor,
+			fmt.Sprintf("You escaped in %s!", formatGameTime(engine.GameTime(frame))), text)
+	}
+}
+
+// drawHUDItems draws the given items in a row, with the bottom left corner of the box at the given position.
+func (v *View) drawHUDItems(pos f32.Point, items []hudItem) {
+	gtx := v.gtx
+	v.drawBox(pos, f32.Point{Y: 1}, func() {
+		children := make([]layout.FlexChild, len(items))
+		for i, item := range items {
+			item := item
+			children[i] = layout.Rigid(func() {
+				layout.Inset{Right: unit.Px(14)}.Layout(gtx, func() {
+					l := v.th.Body1(item.text)
+					l.Color = item.color
+					l.Layout(gtx)
+				})
+			})
+		}
+		layout.Flex{Axis: layout.Horizontal}.Layout(gtx, children...)
+	})
+}
+
+// drawBanner draws a large title and a line of text below it in a box.
+// pos and anchor tell where to place the box, see drawBox.
+func (v *View) drawBanner(pos, anchor f32.Point, titleColor color.RGBA, title, text string) {
+	gtx := v.gtx
+	v.drawBox(pos, anchor, func() {
+		layout.Flex{Axis: layout.Vertical, Alignment: layout.Middle}.Layout(gtx,
+			layout.Rigid(func() {
+				l := v.th.H5(title)
+				l.Color = titleColor
+				l.Layout(gtx)
+			}),
+			layout.Rigid(func() {
+				l := v.th.Body1(text)
+				l.Color = hudTextColor
+				l.Layout(gtx)
+			}),
+		)
+	})
+}
+
+// bulldogsNearby returns the number of Bulldogs within nearbyBlocks of Gopher.
+// The model must be locked for reading.
+func bulldogsNearby(m *engine.Model) int {
+	const maxDist = nearbyBlocks * engine.BlockSize
+	n := 0
+	for _, bd := range m.Bulldogs {
+		if math.Hypot(bd.Pos.X-m.Gopher.Pos.X, bd.Pos.Y-m.Gopher.Pos.Y) <= maxDist {
+			n++
+		}
+	}
+	return n
+}
+
+// formatGameTime formats the given game time in the form of "m:ss".
+func formatGameTime(d time.Duration) string {
+	return fmt.Sprintf("%d:%02d", d/time.Minute, d%time.Minute/time.Second)
+}
@@ -0,0 +1,91 @@
+package view
+
+import (
+	"image"
+
+	"github.com/icza/golab/engine"
+	"github.com/icza/golab/render"
+)
+
+const (
+	// labTileBlocks is the number of blocks in a row and in a column of a lab tile.
+	labTileBlocks = 16
+
+	// maxLabTiles is the max number of lab tiles kept in the cache.
+	// When exceeded, tiles that are not visible are evicted.
+	maxLabTiles = 64
+)
+
+// labTile is a cached piece of the lab image.
+type labTile struct {
+	iop imageOp
+
+	// drawSeq is the sequence number of the frame the tile was last drawn in.
+	drawSeq int
+}
+
+// labTiles caches the image of the lab (only the blocks and the exit) split into tiles
+// of labTileBlocks x labTileBlocks blocks. Tiles are rendered lazily when they first become visible,
+// so the image of the whole lab is never created (which would be huge for big labs).
+type labTiles struct {
+	// gameCounter for the cached tiles
+	gameCounter int
+
+	// tiles are the cached tiles, mapped from tile coordinates.
+	tiles map[image.Point]*labTile
+
+	// drawSeq is the sequence number of the last drawn frame.
+	drawSeq int
+}
+
+// drawLabTiles draws the tiles of the lab that are visible in the lab view.
+// The transformation to the lab view origin must be applied.
+// The model must be locked for reading.
+func (v *View) drawLabTiles(m *engine.Model) {
+	lt := &v.labTiles
+	if lt.tiles == nil || lt.gameCounter != m.Counter {
+		lt.tiles = map[image.Point]*labTile{}
+		lt.gameCounter = m.Counter
+	}
+	lt.drawSeq++
+
+	const tilePx = labTileBlocks * engine.BlockSize
+	clip := v.labViewClip
+	min := image.Pt(int(clip.Min.X)/tilePx, int(clip.Min.Y)/tilePx)
+	max := image.Pt(int(clip.Max.X)/tilePx+1, int(clip.Max.Y)/tilePx+1) // exclusive
+	if min.X < 0 {
+		min.X = 0
+	}
+	if min.Y < 0 {
+		min.Y = 0
+	}
+	if n := (m.Cols + labTileBlocks - 1) / labTileBlocks; max.X > n {
+		max.X = n
+	}
+	if n := (m.Rows + labTileBlocks - 1) / labTileBlocks; max.Y > n {
+		max.Y = n
+	}
+
+	for y := min.Y; y < max.Y; y++ {
+		for x := min.X; x < max.X; x++ {
+			tp := image.Pt(x, y)
+			t := lt.tiles[tp]
+			if t == nil {
+				blocks := image.Rectangle{Min: tp, Max: tp.Add(image.Pt(1, 1))}
+				blocks.Min, blocks.Max = blocks.Min.Mul(labTileBlocks), blocks.Max.Mul(labTileBlocks)
+				t = &labTile{iop: newImageOp(render.LabTile(m, &render.Options{Sprites: v.sprites}, blocks))}
+				lt.tiles[tp] = t
+			}
+			t.drawSeq = lt.drawSeq
+			v.drawLabImg(t.iop, float32(x*tilePx), float32(y*tilePx))
+		}
+	}
+
+	if len(lt.tiles) > maxLabTiles {
+		for tp, t := range lt.tiles {
+			if t.drawSeq != lt.drawSeq {
+				delete(lt.tiles, tp)
+			}
+		}
+	}
+}
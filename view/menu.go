@@ -0,0 +1,504 @@
+package view
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/io/key"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"github.com/icza/golab/config"
+	"github.com/icza/golab/engine"
+)
+
+// screen identifies a menu screen.
+type screen int
+
+// Menu screens.
+const (
+	screenTitle screen = iota
+	screenSetup
+	screenPause
+	screenSettings
+	screenHighScores
+	screenCredits
+)
+
+// Colors of the menu.
+var (
+	menuDimColor      = color.RGBA{A: 140}
+	menuItemColor     = color.RGBA{R: 60, G: 60, B: 90, A: 255}
+	menuSelectedColor = color.RGBA{R: 20, G: 130, B: 20, A: 255}
+	menuAdjustColor   = color.RGBA{R: 100, G: 100, A: 255}
+)
+
+// credits is the text of the credits screen.
+var credits = []string{
+	"Gopher's Labyrinth (GoLab) by icza",
+	"https://github.com/icza/golab",
+	"",
+	"Written in Go, the user interface uses gioui.org.",
+	"Gopher is a derivative work based on the Go gopher",
+	"designed by Renee French, licensed under CC BY 3.0.",
+	"The Go font is by Bigelow & Holmes.",
+}
+
+// menuItem is an item of a menu screen.
+type menuItem struct {
+	label string
+
+	// activate is called when the item is chosen (Enter or click), may be nil.
+	activate func()
+
+	// adjust is called to change the value of the item (left / right arrows or the < > buttons), may be nil.
+	adjust func(delta int)
+
+	// opt is the options the value of the item is chosen from with a dropdown list, may be nil.
+	opt *options
+}
+
+// menu is the state of the menu system: a stack of screens, the top one is displayed.
+// While a screen is displayed, the game is paused.
+type menu struct {
+	stack []screen
+
+	// selected is the index of the selected item of the top screen.
+	selected int
+
+	// dropdown is the options whose dropdown list is open, nil if no list is open.
+	dropdown *options
+	// dropdownSel is the index of the highlighted value in the open dropdown list.
+	dropdownSel int
+
+	// Buttons of the items for mouse input, reused across frames.
+	btns, decBtns, incBtns []*widget.Button
+	// choiceBtns are the buttons of the values of the open dropdown list.
+	choiceBtns []*widget.Button
+}
+
+// visible tells if a menu screen is displayed.
+func (mn *menu) visible() bool {
+	return len(mn.stack) > 0
+}
+
+// ShowTitle displays the title screen.
+func (v *View) ShowTitle() {
+	v.pushScreen(screenTitle)
+}
+
+// pushScreen displays the given screen on top of the current one, pausing the game.
+func (v *View) pushScreen(s screen) {
+	if !v.menu.visible() {
+		v.engine.SetPaused(true)
+	}
+	v.menu.stack = append(v.menu.stack, s)
+	v.menu.selected = 0
+	v.menu.dropdown = nil
+}
+
+// popScreen closes the top screen. The game is resumed if no more screens remain.
+func (v *View) popScreen() {
+	mn := &v.menu
+	if !mn.visible() {
+		return
+	}
+	mn.stack = mn.stack[:len(mn.stack)-1]
+	mn.selected, mn.dropdown = 0, nil
+	if !mn.visible() {
+		v.engine.SetPaused(false)
+	}
+}
+
+// closeMenu closes all screens and resumes the game.
+func (v *View) closeMenu() {
+	for v.menu.visible() {
+		v.popScreen()
+	}
+}
+
+// openDropdown displays the menu screen of the given options with its dropdown list open.
+func (v *View) openDropdown(o *options) {
+	mn := &v.menu
+	if !mn.visible() || mn.stack[len(mn.stack)-1] != o.screen {
+		v.pushScreen(o.screen)
+	}
+	_, _, items := v.menuContent(o.screen)
+	for i, item := range items {
+		if item.opt == o {
+			mn.selected = i
+		}
+	}
+	mn.dropdown, mn.dropdownSel = o, o.idx
+}
+
+// activateItem does the job of choosing the given menu item (Enter or click):
+// toggles its dropdown list, activates it, or increases its value.
+func (v *View) activateItem(item menuItem) {
+	mn := &v.menu
+	switch {
+	case item.opt != nil:
+		if mn.dropdown == item.opt {
+			mn.dropdown = nil
+		} else {
+			v.openDropdown(item.opt)
+		}
+	case item.activate != nil:
+		item.activate()
+	case item.adjust != nil:
+		item.adjust(1)
+	}
+}
+
+// menuContent returns the title, the text lines and the items of the given screen.
+func (v *View) menuContent(s screen) (title string, lines []string, items []menuItem) {
+	back := menuItem{label: "Back", activate: v.popScreen}
+	newGame := func() {
+		v.closeMenu()
+		v.sendNewGame()
+	}
+	// play continues the current game if it has not started yet (it may be set up by command line flags),
+	// else starts a new game.
+	play := func() {
+		m := v.engine.Model
+		m.RLock()
+		fresh := m.Frame == 0
+		m.RUnlock()
+		if !fresh {
+			newGame()
+			return
+		}
+		v.closeMenu()
+	}
+	onOff := func(b bool) string {
+		if b {
+			return "On"
+		}
+		return "Off"
+	}
+
+	switch s {
+	case screenTitle:
+		title = "Gopher's Labyrinth"
+		lines = []string{"Guide Gopher to the exit, beware of the Bulldogs!"}
+		items = []menuItem{
+			{label: "Play", activate: play},
+			{label: "New game setup", activate: func() { v.pushScreen(screenSetup) }},
+			{label: "Settings", activate: func() { v.pushScreen(screenSettings) }},
+			{label: "High scores", activate: func() { v.pushScreen(screenHighScores) }},
+			{label: "Credits", activate: func() { v.pushScreen(screenCredits) }},
+		}
+
+	case screenSetup:
+		title = "New Game"
+		items = []menuItem{
+			{label: fmt.Sprintf("Difficulty: %s ▼", v.diffOpt.selected()), opt: v.diffOpt},
+			{label: fmt.Sprintf("Lab size: %s ▼", v.labSizeOpt.selected()), opt: v.labSizeOpt},
+			{label: fmt.Sprintf("Speed: %s ▼", v.speedOpt.selected()), opt: v.speedOpt},
+			{label: "Start game", activate: newGame},
+			back,
+		}
+
+	case screenPause:
+		title = "Paused"
+		items = []menuItem{
+			{label: "Resume", activate: v.closeMenu},
+			{label: "New game", activate: newGame},
+			{label: "New game setup", activate: func() { v.pushScreen(screenSetup) }},
+			{label: "Settings", activate: func() { v.pushScreen(screenSettings) }},
+			{label: "High scores", activate: func() { v.pushScreen(screenHighScores) }},
+			{label: "Main menu", activate: func() { v.pushScreen(screenTitle) }},
+		}
+
+	case screenSettings:
+		title = "Settings"
+		if v.themeOpt != nil {
+			items = append(items, menuItem{label: fmt.Sprintf("Theme: %s ▼", v.themeOpt.selected()), opt: v.themeOpt})
+		}
+		if v.toggleFullscreen != nil {
+			items = append(items, menuItem{label: "Toggle fullscreen", activate: v.toggleFullscreen})
+		}
+		items = append(items,
+			menuItem{
+				label: fmt.Sprintf("Zoom: %d%%", int(v.camera.targetZoom*100+0.5)),
+				adjust: func(delta int) {
+					if delta > 0 {
+						v.camera.zoomBy(zoomStep)
+					} else {
+						v.camera.zoomBy(1 / zoomStep)
+					}
+				},
+			},
+			menuItem{label: fmt.Sprintf("Minimap: %s", onOff(v.minimap.visible)), activate: func() { v.minimap.visible = !v.minimap.visible }},
+			menuItem{label: fmt.Sprintf("D-pad: %s", onOff(v.showDPad)), activate: func() { v.showDPad = !v.showDPad }},
+			menuItem{label: "Controls...", activate: func() { v.controls.visible, v.controls.message = true, "" }},
+			back,
+		)
+
+	case screenHighScores:
+		title = "High Scores"
+		if len(v.highScores) == 0 {
+			lines = append(lines, "No high scores yet.")
+		}
+		for i, h := range v.highScores {
+			lines = append(lines, fmt.Sprintf("%2d. %6d   %s, %s, %s, %d hints   %s", i+1, h.Score,
+				h.Difficulty, h.LabSize, formatGameTime(engine.GameTime(h.Frames)), h.Hints, h.Date.Format("2006-01-02")))
+		}
+		items = []menuItem{back}
+
+	case screenCredits:
+		title = "Credits"
+		lines = credits
+		items = []menuItem{back}
+	}
+
+	return
+}
+
+// handleMenuKey handles a key event while a menu screen is displayed.
+//
+// Up and down select an item, Enter chooses it (or opens its dropdown list), left and right change its value,
+// Escape closes the screen.
+func (v *View) handleMenuKey(e key.Event) {
+	mn := &v.menu
+	if mn.dropdown != nil {
+		v.handleDropdownKey(e)
+		return
+	}
+	_, _, items := v.menuContent(mn.stack[len(mn.stack)-1])
+	if mn.selected >= len(items) {
+		mn.selected = len(items) - 1
+	}
+	item := items[mn.selected]
+
+	switch e.Name {
+	case key.NameEscape:
+		v.popScreen()
+	case key.NameUpArrow:
+		mn.selected = (mn.selected + len(items) - 1) % len(items)
+	case key.NameDownArrow:
+		mn.selected = (mn.selected + 1) % len(items)
+	case key.NameLeftArrow:
+		if item.adjust != nil {
+			item.adjust(-1)
+		}
+	case key.NameRightArrow:
+		if item.adjust != nil {
+			item.adjust(1)
+		}
+	case key.NameReturn, key.NameEnter:
+		v.activateItem(item)
+	}
+}
+
+// handleDropdownKey handles a key event while a dropdown list is open.
+//
+// Up and down highlight a value, Enter chooses it, Escape closes the list.
+func (v *View) handleDropdownKey(e key.Event) {
+	mn := &v.menu
+	switch e.Name {
+	case key.NameEscape:
+		mn.dropdown = nil
+	case key.NameUpArrow:
+		if mn.dropdownSel > 0 {
+			mn.dropdownSel--
+		}
+	case key.NameDownArrow:
+		if mn.dropdownSel < mn.dropdown.len()-1 {
+			mn.dropdownSel++
+		}
+	case key.NameReturn, key.NameEnter:
+		mn.dropdown.idx = mn.dropdownSel
+		mn.dropdown = nil
+	}
+}
+
+// drawMenu draws the top menu screen if a screen is displayed, and handles clicks on its items.
+func (v *View) drawMenu() {
+	mn := &v.menu
+	if !mn.visible() {
+		return
+	}
+	s := mn.stack[len(mn.stack)-1]
+
+	// Handle clicks first, they may change the screen:
+	_, _, items := v.menuContent(s)
+	for len(mn.btns) < len(items) {
+		mn.btns = append(mn.btns, new(widget.Button))
+		mn.decBtns = append(mn.decBtns, new(widget.Button))
+		mn.incBtns = append(mn.incBtns, new(widget.Button))
+	}
+	if o := mn.dropdown; o != nil {
+		for i := 0; i < o.len() && i < len(mn.choiceBtns); i++ {
+			for mn.choiceBtns[i].Clicked(v.gtx) {
+				o.idx, mn.dropdown = i, nil
+			}
+		}
+	}
+	for i, item := range items {
+		for mn.btns[i].Clicked(v.gtx) {
+			mn.selected = i
+			v.activateItem(item)
+		}
+		for mn.decBtns[i].Clicked(v.gtx) {
+			mn.selected = i
+			if item.adjust != nil {
+				item.adjust(-1)
+			}
+		}
+		for mn.incBtns[i].Clicked(v.gtx) {
+			mn.selected = i
+			if item.adjust != nil {
+				item.adjust(1)
+			}
+		}
+	}
+	if !mn.visible() {
+		return
+	}
+
+	title, lines, items := v.menuContent(mn.stack[len(mn.stack)-1])
+	if mn.selected >= len(items) {
+		mn.selected = len(items) - 1
+	}
+
+	r := v.labViewRect()
+	v.fillRect(r, menuDimColor)
+
+	gtx, th := v.gtx, v.th
+	children := []layout.FlexChild{
+		layout.Rigid(func() {
+			l := th.H4(title)
+			l.Color = hudTextColor
+			layout.UniformInset(unit.Px(8)).Layout(gtx, func() { l.Layout(gtx) })
+		}),
+	}
+	for _, line := range lines {
+		line := line
+		children = append(children, layout.Rigid(func() {
+			l := th.Body1(line)
+			l.Color = hudTextColor
+			l.Layout(gtx)
+		}))
+	}
+	for i, item := range items {
+		i, item := i, item
+		children = append(children, layout.Rigid(func() {
+			layout.Inset{Top: unit.Px(6)}.Layout(gtx, func() {
+				v.layoutMenuItem(i, item)
+			})
+		}))
+		if item.opt != nil && item.opt == mn.dropdown {
+			children = append(children, layout.Rigid(func() {
+				v.layoutDropdown(item.opt)
+			}))
+		}
+	}
+
+	v.drawBox(r.Min.Add(r.Max).Mul(0.5), f32.Point{X: 0.5, Y: 0.5}, func() {
+		layout.Flex{Axis: layout.Vertical, Alignment: layout.Middle}.Layout(gtx, children...)
+	})
+}
+
+// layoutMenuItem lays out the menu item of the given index: a button,
+// with buttons to decrease and increase its value if it is adjustable.
+func (v *View) layoutMenuItem(i int, item menuItem) {
+	gtx, th, mn := v.gtx, v.th, &v.menu
+
+	adjustBtn := func(txt string, btn *widget.Button) layout.FlexChild {
+		return layout.Rigid(func() {
+			layout.Inset{Left: unit.Px(4), Right: unit.Px(4)}.Layout(gtx, func() {
+				b := th.Button(txt)
+				b.Background = menuAdjustColor
+				b.Layout(gtx, btn)
+			})
+		})
+	}
+
+	var children []layout.FlexChild
+	if item.adjust != nil {
+		children = append(children, adjustBtn("<", mn.decBtns[i]))
+	}
+	children = append(children, layout.Rigid(func() {
+		b := th.Button(item.label)
+		b.Background = menuItemColor
+		if i == mn.selected {
+			b.Background = menuSelectedColor
+		}
+		b.Layout(gtx, mn.btns[i])
+	}))
+	if item.adjust != nil {
+		children = append(children, adjustBtn(">", mn.incBtns[i]))
+	}
+	layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx, children...)
+}
+
+// layoutDropdown lays out the open dropdown list of the given options:
+// a button for each value, the current value is marked, the highlighted one is selected.
+func (v *View) layoutDropdown(o *options) {
+	gtx, th, mn := v.gtx, v.th, &v.menu
+	for len(mn.choiceBtns) < o.len() {
+		mn.choiceBtns = append(mn.choiceBtns, new(widget.Button))
+	}
+
+	children := make([]layout.FlexChild, o.len())
+	for i := range children {
+		i := i
+		children[i] = layout.Rigid(func() {
+			label := fmt.Sprint(o.value(i))
+			if i == o.idx {
+				label = "• " + label
+			}
+			b := th.Button(label)
+			b.Background = menuAdjustColor
+			if i == mn.dropdownSel {
+				b.Background = menuSelectedColor
+			}
+			layout.Inset{Top: unit.Px(2)}.Layout(gtx, func() {
+				b.Layout(gtx, mn.choiceBtns[i])
+			})
+		})
+	}
+	layout.Inset{Left: unit.Px(20)}.Layout(gtx, func() {
+		layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+// SetHighScores sets the high scores to display, and the function that saves them.
+// Scores of won games are only recorded if save is not nil.
+func (v *View) SetHighScores(hs config.HighScores, save func(hs config.HighScores) error) {
+	v.highScores, v.saveHighScores = hs, save
+}
+
+// checkHighScore records the score of the current game if it is won by the user and qualifies.
+func (v *View) checkHighScore() {
+	m := v.engine.Model
+	m.RLock()
+	if !m.Won || m.Counter == v.scoreCounter {
+		m.RUnlock()
+		return
+	}
+	v.scoreCounter, v.scoreRank = m.Counter, -1
+	h := config.HighScore{
+		Score:      m.Score,
+		Difficulty: m.Difficulty.Name,
+		LabSize:    fmt.Sprintf("%dx%d", m.Cols, m.Rows),
+		Frames:     m.Frame,
+		Hints:      m.HintsUsed,
+		Date:       time.Now(),
+	}
+	m.RUnlock()
+
+	if v.saveHighScores == nil || v.autopilot {
+		return
+	}
+	if v.highScores, v.scoreRank = v.highScores.Add(h); v.scoreRank < 0 {
+		return
+	}
+	if err := v.saveHighScores(v.highScores); err != nil {
+		log.Printf("Failed to save high scores: %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package view
+
+import (
+	"testing"
+
+	"gioui.org/io/key"
+	"github.com/icza/golab/engine"
+)
+
+func TestMenuDropdown(t *testing.T) {
+	v := newTestView()
+	v.diffOpt = newOptions(v, screenSetup, "[D]ifficulty", engine.Difficulties, engine.DifficultyDefaultIdx)
+	v.labSizeOpt = newOptions(v, screenSetup, "[L]ab size", engine.LabSizes, engine.LabSizeDefaultIdx)
+	v.speedOpt = newOptions(v, screenSetup, "[S]peed", engine.Speeds, engine.SpeedDefaultIdx)
+
+	press := func(names ...string) {
+		for _, name := range names {
+			v.handleKey(key.Event{Name: name})
+		}
+	}
+
+	// Open the lab size dropdown on the setup screen, choose the next size:
+	v.pushScreen(screenSetup)
+	press(key.NameDownArrow, key.NameReturn)
+	if v.menu.dropdown != v.labSizeOpt {
+		t.Fatalf("Expected open lab size dropdown")
+	}
+	press(key.NameDownArrow, key.NameReturn)
+	if exp := engine.LabSizeDefaultIdx + 1; v.labSizeOpt.idx != exp || v.menu.dropdown != nil {
+		t.Errorf("Expected lab size %d and closed dropdown, got: %d (%v)", exp, v.labSizeOpt.idx, v.menu.dropdown)
+	}
+
+	// Escape closes the dropdown without changing the value, then the screen:
+	v.openDropdown(v.diffOpt)
+	press(key.NameUpArrow, key.NameEscape)
+	if v.diffOpt.idx != engine.DifficultyDefaultIdx || v.menu.dropdown != nil || !v.menu.visible() {
+		t.Errorf("Expected unchanged difficulty and closed dropdown")
+	}
+	press(key.NameEscape)
+	if v.menu.visible() {
+		t.Errorf("Expected closed menu")
+	}
+}
@@ -0,0 +1,266 @@
+package view
+
+import (
+	"image"
+	"image/color"
+
+	"gioui.org/f32"
+	"gioui.org/op/paint"
+	"github.com/icza/golab/engine"
+)
+
+const (
+	// minimapMaxPx is the max size of the minimap in pixels.
+	// Scale is chosen so the minimap fits in this: a whole number of pixels is used for each block if possible,
+	// else the lab is downsampled (multiple blocks share a pixel).
+	minimapMaxPx = 200
+
+	// minimapMaxScale is the max number of pixels used for each block on the minimap.
+	minimapMaxScale = 3
+
+	// minimapMarginPx is the distance of the minimap from the edges of the lab view.
+	minimapMarginPx = 10
+)
+
+// Colors of the minimap.
+var (
+	minimapWallColor    = color.RGBA{R: 0x90, G: 0x90, B: 0x90, A: 0xff}
+	minimapEmptyColor   = color.RGBA{A: 0xc0}
+	minimapExitColor    = color.RGBA{G: 0xff, A: 0xff}
+	minimapGopherColor  = color.RGBA{R: 0x50, G: 0xd0, B: 0xff, A: 0xff}
+	minimapBulldogColor = color.RGBA{R: 0xff, G: 0x30, A: 0xff}
+	minimapPathColor    = color.RGBA{R: 0xff, G: 0xd7, A: 0xff}
+	minimapViewColor    = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x60}
+)
+
+// minimap is the overview of the whole labyrinth, displayed in the top right corner of the lab view.
+type minimap struct {
+	// visible tells if the minimap is shown.
+	visible bool
+
+	// gameCounter for the cached data
+	gameCounter int
+	// cached ImageOp of the minimap (only the blocks and the exit)
+	imgOp imageOp
+	// scale is the number of pixels used for each block
+	scale float32
+
+	// rect is the rectangle of the minimap in window coordinates where it was last drawn.
+	// Used to tell if a click is on the minimap.
+	rect f32.Rectangle
+}
+
+// ensureMinimapImgOp makes sure the minimap image of the current game is ready.
+// The model must be locked for reading.
+func (v *View) ensureMinimapImgOp(m *engine.Model) {
+	mm := &v.minimap
+	if mm.gameCounter == m.Counter && mm.imgOp.src != nil {
+		return
+	}
+
+	dim := m.Rows
+	if m.Cols > dim {
+		dim = m.Cols
+	}
+	var img *image.RGBA
+	if scale := minimapMaxPx / dim; scale >= 1 {
+		if scale > minimapMaxScale {
+			scale = minimapMaxScale
+		}
+		img = minimapImage(m, scale)
+		mm.scale = float32(scale)
+	} else {
+		blocks := (dim + minimapMaxPx - 1) / minimapMaxPx
+		img = minimapImageDownsampled(m, blocks)
+		mm.scale = 1 / float32(blocks)
+	}
+
+	mm.imgOp = newImageOp(img)
+	mm.gameCounter = m.Counter
+}
+
+// minimapImage returns the minimap image of the lab using scale x scale pixels for each block.
+// The model must be locked for reading.
+func minimapImage(m *engine.Model, scale int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, m.Cols*scale, m.Rows*scale))
+	for row := range m.Lab {
+		for col, block := range m.Lab[row] {
+			c := minimapEmptyColor
+			if block == engine.BlockWall {
+				c = minimapWallColor
+			}
+			if m.ExitBlock.X == col && m.ExitBlock.Y == row {
+				c = minimapExitColor
+			}
+			for y := row * scale; y < (row+1)*scale; y++ {
+				for x := col * scale; x < (col+1)*scale; x++ {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// minimapImageDownsampled returns the minimap image of the lab using a pixel for each blocks x blocks blocks.
+// The color of a pixel is the mix of the wall and empty colors in the ratio of walls in its blocks.
+// The model must be locked for reading.
+func minimapImageDownsampled(m *engine.Model, blocks int) *image.RGBA {
+	w, h := (m.Cols+blocks-1)/blocks, (m.Rows+blocks-1)/blocks
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	walls, total := make([]int, w*h), make([]int, w*h)
+	for row := range m.Lab {
+		for col, block := range m.Lab[row] {
+			i := row/blocks*w + col/blocks
+			total[i]++
+			if block == engine.BlockWall {
+				walls[i]++
+			}
+		}
+	}
+
+	mix := func(a, b uint8, r float64) uint8 {
+		return uint8(float64(a)*(1-r) + float64(b)*r + 0.5)
+	}
+	e, wc := minimapEmptyColor, minimapWallColor
+	for i := range total {
+		r := float64(walls[i]) / float64(total[i])
+		img.Pix[i*4] = mix(e.R, wc.R, r)
+		img.Pix[i*4+1] = mix(e.G, wc.G, r)
+		img.Pix[i*4+2] = mix(e.B, wc.B, r)
+		img.Pix[i*4+3] = mix(e.A, wc.A, r)
+	}
+	img.SetRGBA(m.ExitBlock.X/blocks, m.ExitBlock.Y/blocks, minimapExitColor)
+	return img
+}
+
+// drawMinimap draws the minimap if it's visible.
+func (v *View) drawMinimap() {
+	mm := &v.minimap
+	if !mm.visible {
+		return
+	}
+
+	m := v.engine.Model
+	m.RLock()
+	defer m.RUnlock()
+
+	v.ensureMinimapImgOp(m)
+
+	size := mm.imgOp.src.Bounds().Size()
+	lr := v.labViewRect()
+	pos := f32.Point{
+		X: lr.Max.X - float32(size.X) - minimapMarginPx,
+		Y: lr.Min.Y + minimapMarginPx,
+	}
+	mm.rect = f32.Rectangle{Min: pos, Max: pos.Add(f32.Point{X: float32(size.X), Y: float32(size.Y)})}
+
+	v.drawImg(mm.imgOp, pos.X, pos.Y)
+
+	// toMinimap converts a point in lab coordinates to window coordinates on the minimap:
+	toMinimap := func(x, y float64) f32.Point {
+		return pos.Add(f32.Point{X: float32(x) / engine.BlockSize * mm.scale, Y: float32(y) / engine.BlockSize * mm.scale})
+	}
+
+	// Visible part of the lab:
+	vr := f32.Rectangle{
+		Min: toMinimap(float64(v.labViewClip.Min.X), float64(v.labViewClip.Min.Y)),
+		Max: toMinimap(float64(v.labViewClip.Max.X), float64(v.labViewClip.Max.Y)),
+	}
+	v.fillRect(vr, minimapViewColor)
+
+	// Queued path: targets are always in the same row or column as the previous one.
+	prev := toMinimap(m.Gopher.Pos.X, m.Gopher.Pos.Y)
+	targets := append([]image.Point{m.Gopher.TargetPos}, m.TargetPoss...)
+	for _, tp := range targets {
+		p := toMinimap(float64(tp.X), float64(tp.Y))
+		r := f32.Rectangle{Min: prev, Max: p}.Canon()
+		r.Max = r.Max.Add(f32.Point{X: 1, Y: 1})
+		v.fillRect(r, minimapPathColor)
+		prev = p
+	}
+
+	// Bulldogs:
+	if m.Difficulty.Params().MinimapBulldogs {
+		for _, bd := range m.Bulldogs {
+			v.fillRect(minimapDot(toMinimap(bd.Pos.X, bd.Pos.Y), mm.scale), minimapBulldogColor)
+		}
+	}
+
+	// Gopher:
+	v.fillRect(minimapDot(toMinimap(m.Gopher.Pos.X, m.Gopher.Pos.Y), mm.scale), minimapGopherColor)
+}
+
+// minimapBlock returns the block at the given position (in blocks) clicked on the minimap.
+// If the minimap is downsampled (scale < 1), the clicked pixel covers multiple blocks,
+// and the free block closest to the position is returned from them.
+// The model must be locked for reading.
+func minimapBlock(m *engine.Model, x, y, scale float32) image.Point {
+	p := image.Pt(int(x), int(y))
+	if scale >= 1 {
+		return p
+	}
+
+	// Blocks covered by the pixel:
+	blocks := int(1/scale + 0.5)
+	r := image.Rectangle{Min: p.Div(blocks).Mul(blocks)}
+	r.Max = r.Min.Add(image.Pt(blocks, blocks))
+	r = r.Intersect(image.Rect(0, 0, m.Cols, m.Rows))
+	best, bestDist := p, -1
+	for row := r.Min.Y; row < r.Max.Y; row++ {
+		for col := r.Min.X; col < r.Max.X; col++ {
+			if m.Lab[row][col] == engine.BlockWall {
+				continue
+			}
+			if d := (col-p.X)*(col-p.X) + (row-p.Y)*(row-p.Y); bestDist < 0 || d < bestDist {
+				best, bestDist = image.Pt(col, row), d
+			}
+		}
+	}
+	return best
+}
+
+// minimapDot returns the rectangle of a dot on the minimap centered at the given point.
+func minimapDot(center f32.Point, scale float32) f32.Rectangle {
+	half := scale/2 + 1
+	return f32.Rectangle{
+		Min: center.Sub(f32.Point{X: half, Y: half}),
+		Max: center.Add(f32.Point{X: half, Y: half}),
+	}
+}
+
+// fillRect fills the given rectangle with the given color.
+func (v *View) fillRect(r f32.Rectangle, c color.RGBA) {
+	paint.ColorOp{Color: c}.Add(v.gtx.Ops)
+	paint.PaintOp{Rect: r}.Add(v.gtx.Ops)
+}
+
+// minimapClick handles a click at the given window position.
+// If the click is on the minimap, the path from the last queued target to the clicked block
+// is queued (as much as fits into the queue of targets), and true is returned.
+func (v *View) minimapClick(pos f32.Point) bool {
+	mm := &v.minimap
+	if !mm.visible {
+		return false
+	}
+	r := mm.rect
+	if pos.X < r.Min.X || pos.X >= r.Max.X || pos.Y < r.Min.Y || pos.Y >= r.Max.Y {
+		return false
+	}
+
+	m := v.engine.Model
+	m.RLock()
+	to := minimapBlock(m, (pos.X-r.Min.X)/mm.scale, (pos.Y-r.Min.Y)/mm.scale, mm.scale)
+	last := m.Gopher.TargetPos
+	if len(m.TargetPoss) > 0 {
+		last = m.TargetPoss[len(m.TargetPoss)-1]
+	}
+	path := engine.FindPath(m.Lab, engine.BlockOf(last.X, last.Y), to, nil)
+	m.RUnlock()
+
+	for _, wp := range engine.Waypoints(path) {
+		c := engine.BlockCenter(wp)
+		v.engine.SendClick(engine.Click{X: c.X, Y: c.Y, Left: true})
+	}
+	return true
+}
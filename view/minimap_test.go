@@ -0,0 +1,71 @@
+package view
+
+import (
+	"image"
+	"testing"
+
+	"github.com/icza/golab/engine"
+)
+
+// newTestModel returns a model of a rows x cols lab having walls at even rows and columns.
+func newTestModel(rows, cols int) *engine.Model {
+	m := &engine.Model{Rows: rows, Cols: cols, ExitBlock: image.Pt(cols-2, rows-2)}
+	m.Lab = make([][]engine.Block, rows)
+	for row := range m.Lab {
+		m.Lab[row] = make([]engine.Block, cols)
+		for col := range m.Lab[row] {
+			if row%2 == 0 || col%2 == 0 {
+				m.Lab[row][col] = engine.BlockWall
+			}
+		}
+	}
+	return m
+}
+
+func TestMinimapSize(t *testing.T) {
+	cases := []struct {
+		size   int
+		scale  float32
+		wantPx int
+	}{
+		{11, minimapMaxScale, 11 * minimapMaxScale},
+		{99, 2, 198},
+		{199, 1, 199},
+		{201, 0.5, 101},
+		{1001, 1.0 / 6, 167},
+	}
+
+	for _, c := range cases {
+		v := &View{engine: &engine.Engine{Model: newTestModel(c.size, c.size)}}
+		v.ensureMinimapImgOp(v.engine.Model)
+		mm := &v.minimap
+		if mm.scale != c.scale {
+			t.Errorf("[size: %d] Expected scale %v, got: %v", c.size, c.scale, mm.scale)
+		}
+		if got := mm.imgOp.src.Bounds().Size(); got != image.Pt(c.wantPx, c.wantPx) {
+			t.Errorf("[size: %d] Expected image size %d, got: %v", c.size, c.wantPx, got)
+		}
+	}
+}
+
+func TestMinimapBlock(t *testing.T) {
+	m := newTestModel(1001, 1001)
+	const scale = 1.0 / 6 // 6x6 blocks per pixel
+
+	cases := []struct {
+		x, y float32
+		exp  image.Point
+	}{
+		{1.2, 1.7, image.Pt(1, 1)},
+		{4.9, 4.9, image.Pt(3, 3)},       // (4, 4) is wall, (3, 3) is the closest free block
+		{5.1, 5.1, image.Pt(5, 5)},       // same pixel, free block
+		{6.1, 6.1, image.Pt(7, 7)},       // next pixel
+		{1000, 1000, image.Pt(999, 999)}, // last pixel only has 5 rows and columns
+	}
+
+	for _, c := range cases {
+		if got := minimapBlock(m, c.x, c.y, scale); got != c.exp {
+			t.Errorf("[x: %v, y: %v] Expected %v, got: %v", c.x, c.y, c.exp, got)
+		}
+	}
+}
@@ -12,25 +12,30 @@ import (
 
 // options groups functionality to present a widget that can be used to select one option from many.
 //
-// This implementation uses a Button which loops through the possible values on clicks.
+// This implementation uses a Button which opens the menu screen of the options
+// with a dropdown list of the possible values.
 type options struct {
 	v      *View
 	title  string
 	values interface{}
 	idx    int // selected index
 
+	// screen is the menu screen where the value can be chosen from a dropdown list.
+	screen screen
+
 	btn *widget.Button
 }
 
 // newOptions creates a new options.
 //
 // values must be a slice of possible values.
-func newOptions(v *View, title string, values interface{}, defaultIdx int) *options {
+func newOptions(v *View, s screen, title string, values interface{}, defaultIdx int) *options {
 	return &options{
 		v:      v,
 		title:  title,
 		values: values,
 		idx:    defaultIdx,
+		screen: s,
 		btn:    new(widget.Button),
 	}
 }
@@ -38,25 +43,36 @@ func newOptions(v *View, title string, values interface{}, defaultIdx int) *opti
 // handleInput handles user inputs that may change the selected option.
 func (o *options) handleInput() {
 	for o.btn.Clicked(o.v.gtx) {
-		o.onClick()
+		o.v.openDropdown(o)
 	}
 }
 
-// onClick does the job needed when the option is clicked: rotates the selected option.
-func (o *options) onClick() {
-	o.idx = (o.idx + 1) % reflect.ValueOf(o.values).Len()
+// adjust changes the selected option by delta, wrapping around at the ends.
+func (o *options) adjust(delta int) {
+	n := o.len()
+	o.idx = ((o.idx+delta)%n + n) % n
+}
+
+// len returns the number of values.
+func (o *options) len() int {
+	return reflect.ValueOf(o.values).Len()
 }
 
 // selected returns the selected item.
 func (o *options) selected() interface{} {
-	return reflect.ValueOf(o.values).Index(o.idx).Interface()
+	return o.value(o.idx)
+}
+
+// value returns the value at the given index.
+func (o *options) value(idx int) interface{} {
+	return reflect.ValueOf(o.values).Index(idx).Interface()
 }
 
 // layout lays out the UI widget
 func (o *options) layout() {
 	layout.Inset{Left: unit.Px(5), Right: unit.Px(5)}.Layout(o.v.gtx, func() {
 		b := o.v.th.Button(
-			fmt.Sprintf("%s: %s", o.title, o.selected()),
+			fmt.Sprintf("%s: %s ▼", o.title, o.selected()),
 		)
 		b.Background = color.RGBA{R: 100, G: 100, A: 255}
 		b.Layout(o.v.gtx, o.btn)
@@ -0,0 +1,87 @@
+package view
+
+import (
+	"fmt"
+	"image/color"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+)
+
+// drawTextBox draws the given lines of text on a semi-transparent background,
+// with its top left corner at the given position.
+func (v *View) drawTextBox(pos f32.Point, lines ...string) {
+	gtx := v.gtx
+	v.drawBox(pos, f32.Point{}, func() {
+		children := make([]layout.FlexChild, len(lines))
+		for i, line := range lines {
+			line := line
+			children[i] = layout.Rigid(func() {
+				l := v.th.Body1(line)
+				l.Color = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+				l.Layout(gtx)
+			})
+		}
+		layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+// drawBox draws the given widget with an inset on a semi-transparent background.
+// anchor tells which point of the box is placed at the given position,
+// relative to the size of the box: (0, 0) is the top left corner, (0.5, 0.5) is the center.
+func (v *View) drawBox(pos, anchor f32.Point, w layout.Widget) {
+	gtx := v.gtx
+
+	var stack op.StackOp
+	stack.Push(gtx.Ops)
+	defer stack.Pop()
+
+	// Record the content so we know the size of the box before positioning and painting it.
+	var macro op.MacroOp
+	macro.Record(gtx.Ops)
+	layout.UniformInset(unit.Px(8)).Layout(gtx, w)
+	macro.Stop()
+
+	size := f32.Point{X: float32(gtx.Dimensions.Size.X), Y: float32(gtx.Dimensions.Size.Y)}
+	op.TransformOp{}.Offset(pos.Sub(f32.Point{X: size.X * anchor.X, Y: size.Y * anchor.Y})).Add(gtx.Ops)
+
+	paint.ColorOp{Color: color.RGBA{A: 190}}.Add(gtx.Ops)
+	paint.PaintOp{Rect: f32.Rectangle{Max: size}}.Add(gtx.Ops)
+
+	macro.Add()
+}
+
+// drawMetrics draws the metrics of the lab if enabled and the game is over.
+func (v *View) drawMetrics() {
+	if !v.showMetrics {
+		return
+	}
+
+	m := v.engine.Model
+	m.RLock()
+	over := m.Won || m.Dead
+	if over && v.metricsCounter != m.Counter {
+		v.metrics = m.Analyze()
+		v.metricsCounter = m.Counter
+	}
+	m.RUnlock()
+
+	if !over {
+		return
+	}
+
+	mt := v.metrics
+	pos := v.labViewRect().Min.Add(f32.Point{X: 10, Y: 10})
+	v.drawTextBox(pos,
+		fmt.Sprintf("Solution length: %d", mt.SolutionLength),
+		fmt.Sprintf("Decision points: %d", mt.DecisionPoints),
+		fmt.Sprintf("Dead ends: %d (mean depth: %.1f, max: %d)", mt.DeadEnds, mt.MeanDeadEndDepth, mt.MaxDeadEndDepth),
+		fmt.Sprintf("Branching factor: %.2f", mt.BranchingFactor),
+		fmt.Sprintf("River factor: %.2f", mt.RiverFactor),
+		fmt.Sprintf("Longest corridor: %d", mt.LongestCorridor),
+		fmt.Sprintf("Difficulty rating: %.1f", mt.Rating),
+	)
+}
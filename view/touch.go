@@ -0,0 +1,191 @@
+package view
+
+import (
+	"image/color"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/io/pointer"
+	"github.com/icza/golab/engine"
+)
+
+const (
+	// tapSlopPx is the max distance a finger may move and still count as a tap or long-press.
+	tapSlopPx = 20
+
+	// swipeMinPx is the min distance a finger must move to count as a swipe.
+	swipeMinPx = 50
+
+	// longPressTime is the min time a finger must be held still to count as a long-press.
+	longPressTime = 500 * time.Millisecond
+
+	// dpadButtonPx is the size of a button of the on-screen D-pad.
+	dpadButtonPx = 60
+
+	// dpadMarginPx is the distance of the D-pad from the edges of the lab view.
+	dpadMarginPx = 20
+)
+
+// Colors of the D-pad.
+var (
+	dpadColor      = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x50}
+	dpadArrowColor = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xb0}
+)
+
+// touchGesture tracks a single-finger touch gesture.
+//
+// Tap queues a target, swipe moves one step in the swipe's direction,
+// and long-press clears the path (like right click).
+type touchGesture struct {
+	// active tells if a gesture is in progress.
+	active bool
+
+	id        pointer.ID
+	start     f32.Point
+	startTime time.Time
+
+	// moved tells if the finger moved too far for a tap or long-press.
+	moved bool
+
+	// longPressed tells if the long-press has already been fired.
+	longPressed bool
+}
+
+// handleTouch handles a touch event not consumed by the camera (multi-touch).
+func (v *View) handleTouch(e pointer.Event) {
+	t := &v.touch
+
+	switch e.Type {
+	case pointer.Press:
+		if v.dpadClick(e.Position) || v.minimapClick(e.Position) {
+			return
+		}
+		*t = touchGesture{active: true, id: e.PointerID, start: e.Position, startTime: time.Now()}
+	case pointer.Move:
+		if !t.active || e.PointerID != t.id {
+			return
+		}
+		if d := e.Position.Sub(t.start); abs32(d.X) > tapSlopPx || abs32(d.Y) > tapSlopPx {
+			t.moved = true
+		}
+	case pointer.Release:
+		if !t.active || e.PointerID != t.id {
+			return
+		}
+		t.active = false
+		if t.longPressed {
+			return
+		}
+
+		d := e.Position.Sub(t.start)
+		switch {
+		case abs32(d.X) >= swipeMinPx || abs32(d.Y) >= swipeMinPx:
+			var dir engine.Dir
+			if abs32(d.X) > abs32(d.Y) {
+				dir = engine.DirRight
+				if d.X < 0 {
+					dir = engine.DirLeft
+				}
+			} else {
+				dir = engine.DirDown
+				if d.Y < 0 {
+					dir = engine.DirUp
+				}
+			}
+			v.engine.SendKey(engine.Key{DirKeys: map[engine.Dir]bool{dir: true}})
+		case !t.moved:
+			v.sendLabClick(t.start, true, false)
+		}
+	case pointer.Cancel:
+		t.active = false
+	}
+}
+
+// checkLongPress fires the long-press of the touch gesture in progress if it's due.
+// It's called on each frame, as there are no events while the finger is held still.
+func (v *View) checkLongPress(now time.Time) {
+	t := &v.touch
+	if !t.active || t.moved || t.longPressed || now.Sub(t.startTime) < longPressTime {
+		return
+	}
+	t.longPressed = true
+	v.sendLabClick(t.start, false, true)
+}
+
+// sendLabClick sends a click to the engine at the given window position
+// if it is inside the visible part of the lab.
+func (v *View) sendLabClick(p f32.Point, left, right bool) {
+	pos := v.toLab(p)
+	r := v.labViewClip
+	if pos.X >= r.Min.X && pos.X < r.Max.X && pos.Y >= r.Min.Y && pos.Y < r.Max.Y {
+		v.engine.SendClick(engine.Click{X: int(pos.X), Y: int(pos.Y), Left: left, Right: right})
+	}
+}
+
+// dpadRects returns the rectangles of the D-pad buttons in window coordinates, indexed by direction.
+// The D-pad is in the bottom left corner of the lab view.
+func (v *View) dpadRects() (rects [engine.DirCount]f32.Rectangle) {
+	lr := v.labViewRect()
+	// Center of the D-pad:
+	c := f32.Point{X: lr.Min.X + dpadMarginPx + 1.5*dpadButtonPx, Y: lr.Max.Y - dpadMarginPx - 1.5*dpadButtonPx}
+	offsets := [engine.DirCount]f32.Point{
+		engine.DirRight: {X: 1},
+		engine.DirLeft:  {X: -1},
+		engine.DirUp:    {Y: -1},
+		engine.DirDown:  {Y: 1},
+	}
+	half := f32.Point{X: dpadButtonPx / 2, Y: dpadButtonPx / 2}
+	for dir, o := range offsets {
+		bc := c.Add(o.Mul(dpadButtonPx))
+		rects[dir] = f32.Rectangle{Min: bc.Sub(half), Max: bc.Add(half)}
+	}
+	return
+}
+
+// dpadClick handles a press at the given window position.
+// If the D-pad is visible and a button of it is pressed, the direction is sent to the engine
+// and true is returned.
+func (v *View) dpadClick(pos f32.Point) bool {
+	if !v.showDPad {
+		return false
+	}
+	for dir, r := range v.dpadRects() {
+		if pos.X >= r.Min.X && pos.X < r.Max.X && pos.Y >= r.Min.Y && pos.Y < r.Max.Y {
+			v.engine.SendKey(engine.Key{DirKeys: map[engine.Dir]bool{engine.Dir(dir): true}})
+			return true
+		}
+	}
+	return false
+}
+
+// drawDPad draws the on-screen D-pad if it's visible.
+func (v *View) drawDPad() {
+	if !v.showDPad {
+		return
+	}
+
+	for dir, r := range v.dpadRects() {
+		v.fillRect(r, dpadColor)
+
+		// Arrow: a triangle approximated by stripes (there is no path drawing used in the view).
+		c := r.Min.Add(r.Max).Mul(0.5)
+		const n, size = 8, dpadButtonPx / 3
+		for i := 0; i < n; i++ {
+			// Stripe i is at distance d from the tip, with half-width growing with d.
+			d := float32(i) * size / n
+			halfW := d * 0.9
+			var s f32.Rectangle
+			switch engine.Dir(dir) {
+			case engine.DirUp:
+				s = f32.Rectangle{Min: f32.Point{X: c.X - halfW, Y: c.Y - size/2 + d}, Max: f32.Point{X: c.X + halfW, Y: c.Y - size/2 + d + size/n}}
+			case engine.DirDown:
+				s = f32.Rectangle{Min: f32.Point{X: c.X - halfW, Y: c.Y + size/2 - d - size/n}, Max: f32.Point{X: c.X + halfW, Y: c.Y + size/2 - d}}
+			case engine.DirLeft:
+				s = f32.Rectangle{Min: f32.Point{X: c.X - size/2 + d, Y: c.Y - halfW}, Max: f32.Point{X: c.X - size/2 + d + size/n, Y: c.Y + halfW}}
+			case engine.DirRight:
+				s = f32.Rectangle{Min: f32.Point{X: c.X + size/2 - d - size/n, Y: c.Y - halfW}, Max: f32.Point{X: c.X + size/2 - d, Y: c.Y + halfW}}
+			}
+			v.fillRect(s, dpadArrowColor)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package view
+
+import (
+	"testing"
+
+	"gioui.org/f32"
+	"gioui.org/io/pointer"
+	"github.com/icza/golab/engine"
+)
+
+// newTestView returns a View without a window, showing the whole lab at zoom 1
+// at the top left corner of the window (so window coordinates equal lab coordinates).
+func newTestView() *View {
+	eng := engine.NewEngine(func() {})
+	m := eng.Model
+	v := &View{
+		engine: eng,
+		camera: newCamera(),
+	}
+	v.labViewClip = f32.Rectangle{Max: f32.Point{X: float32(m.Cols * engine.BlockSize), Y: float32(m.Rows * engine.BlockSize)}}
+	v.labViewZoom = 1
+	return v
+}
+
+func TestHandleTouchTap(t *testing.T) {
+	v := newTestView()
+	m := v.engine.Model
+	v.engine.SetPaused(true) // So Gopher doesn't move and consume the target
+
+	// Tap on Gopher's block:
+	p := f32.Point{X: float32(m.Gopher.TargetPos.X), Y: float32(m.Gopher.TargetPos.Y)}
+	v.handlePointer(pointer.Event{Type: pointer.Press, Source: pointer.Touch, PointerID: 1, Position: p})
+	v.handlePointer(pointer.Event{Type: pointer.Release, Source: pointer.Touch, PointerID: 1, Position: p})
+	v.engine.Step()
+
+	if len(m.TargetPoss) != 1 {
+		t.Errorf("Expected 1 queued target, got: %v", m.TargetPoss)
+	}
+}
+
+func TestHandleTouchTwoFingers(t *testing.T) {
+	v := newTestView()
+
+	v.handlePointer(pointer.Event{Type: pointer.Press, Source: pointer.Touch, PointerID: 1, Position: f32.Point{X: 10, Y: 10}})
+	if !v.touch.active {
+		t.Errorf("Expected active gesture")
+	}
+	v.handlePointer(pointer.Event{Type: pointer.Press, Source: pointer.Touch, PointerID: 2, Position: f32.Point{X: 50, Y: 10}})
+	if v.touch.active {
+		t.Errorf("Expected second finger to cancel the gesture")
+	}
+}
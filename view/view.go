@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"image/draw"
 	"log"
+	"time"
 
 	"gioui.org/app"
 	"gioui.org/f32"
@@ -23,18 +23,19 @@ import (
 	"gioui.org/unit"
 	"gioui.org/widget"
 	"gioui.org/widget/material"
+	"github.com/icza/golab/analysis"
+	"github.com/icza/golab/bot"
+	"github.com/icza/golab/config"
 	"github.com/icza/golab/engine"
+	"github.com/icza/golab/render"
 	"golang.org/x/image/font/gofont/goregular"
 )
 
 const (
-	controlsHeightPx = 70
-	viewWidthPx      = 700
-	viewHeightPx     = 700
 	// WindowWidthPx is the suggested window width
-	WindowWidthPx = viewWidthPx
+	WindowWidthPx = 700
 	// WindowHeightPx is the suggested window height
-	WindowHeightPx = controlsHeightPx + viewHeightPx
+	WindowHeightPx = 770
 )
 
 func init() {
@@ -76,6 +77,7 @@ type View struct {
 
 	// New Game button model
 	newGameBtn *widget.Button
+	hintBtn    *widget.Button
 
 	// Difficulty options
 	diffOpt *options
@@ -83,59 +85,145 @@ type View struct {
 	labSizeOpt *options
 	// Speed options
 	speedOpt *options
+	// Theme options, nil if there are no themes to choose from
+	themeOpt *options
+
+	// genOpts holds the labyrinth generation options of new games
+	genOpts engine.GenOptions
+
+	// keyBindings is the key bindings in use, keys maps key combos to the actions they trigger
+	keyBindings config.KeyBindings
+	keys        map[config.KeyCombo]config.Action
+	// saveKeyBindings saves the key bindings, nil if not supported.
+	saveKeyBindings func(kb config.KeyBindings) error
+
+	// controls is the screen to view and rebind keys
+	controls controlsScreen
+
+	// menu is the stack of menu screens
+	menu    menu
+	menuBtn *widget.Button
+
+	// highScores is the list of high scores, saveHighScores saves them (nil if scores are not to be recorded)
+	highScores     config.HighScores
+	saveHighScores func(hs config.HighScores) error
+	// scoreCounter is the game counter of the last won game, scoreRank is its index in highScores (-1 if not listed)
+	scoreCounter, scoreRank int
+
+	// showMetrics tells if metrics of the lab are to be shown when the game is over
+	showMetrics bool
+	// metrics of the lab of the game identified by metricsCounter
+	metrics        *analysis.Metrics
+	metricsCounter int
+
+	// toggleFullscreen toggles fullscreen mode of the window, nil if not supported.
+	toggleFullscreen func()
+
+	// dirKeys is the state of the direction keys
+	dirKeys dirKeys
+
+	// touch is the single-finger touch gesture in progress
+	touch touchGesture
+
+	// showDPad tells if the on-screen D-pad is visible
+	showDPad bool
+
+	// minimap is the overview of the whole lab
+	minimap minimap
+
+	// autopilot tells if the computer is playing ("watch the computer play" demo mode)
+	autopilot bool
+	// autopilotCtrl is the controller playing in autopilot mode, created on first use if not set.
+	autopilotCtrl engine.Controller
 
 	// Height of controls in pixels
 	controlsHeightPx int
 
+	// Sprites the imageOps are made of.
+	sprites *render.Sprites
+
 	// "static" imageOps
-	imgOpGophers  []imageOp
-	imgOpDead     imageOp
-	imgOpBulldogs []imageOp
-	imgOpMarker   imageOp
-	imgOpExit     imageOp
-	imgOpWon      imageOp
-
-	// gameCounter for the cached data
-	gameCounter int
-	// cached ImageOp of the whole labyrinth (only the blocks)
-	labImgOp imageOp
-
-	// Tells what offset was last applied to draw the lab view.
-	// Used when calculating click position in the lab.
-	labViewOffset f32.Point
-	// labViewClip tells what clip rectangle was applied to draw the lab view.
-	// Used to tell if a click is accepted in the lab.
+	imgOpMarker imageOp
+	imgOpExit   imageOp
+	imgOpWon    imageOp
+
+	// spriteOps caches the imageOps of the sprites of moving objects (including animation frames)
+	spriteOps map[*image.RGBA]imageOp
+
+	// overCounter is the game counter of the game that ended at overSince, for the death and win animations
+	overCounter int
+	overSince   time.Time
+
+	// labTiles caches the image of the labyrinth (only the blocks) in tiles
+	labTiles labTiles
+
+	// camera of the lab view
+	camera camera
+
+	// labViewOrigin is the top left corner of the lab view in window coordinates where it was last drawn.
+	labViewOrigin f32.Point
+	// labViewClip is the visible rectangle of the lab in lab coordinates.
 	labViewClip f32.Rectangle
+	// labViewZoom is the zoom the lab view was last drawn with.
+	labViewZoom float32
 }
 
 // New returns a new View.
 func New(eng *engine.Engine, w *app.Window) *View {
 	v := &View{
-		engine:      eng,
-		w:           w,
-		th:          material.NewTheme(),
-		gtx:         layout.NewContext((w.Queue())),
-		newGameBtn:  new(widget.Button),
-		imgOpDead:   newImageOp(imgDead),
-		imgOpMarker: newImageOp(imgMarker),
-		imgOpExit:   newImageOp(imgExit),
-		imgOpWon:    newImageOp(imgWon),
+		engine:     eng,
+		w:          w,
+		th:         material.NewTheme(),
+		gtx:        layout.NewContext((w.Queue())),
+		newGameBtn: new(widget.Button),
+		hintBtn:    new(widget.Button),
+		menuBtn:    new(widget.Button),
+		camera:     newCamera(),
 	}
+	v.SetKeyBindings(config.DefaultKeyBindings())
 
-	for _, img := range imgGophers {
-		v.imgOpGophers = append(v.imgOpGophers, newImageOp(img))
-	}
-	for _, img := range imgBulldogs {
-		v.imgOpBulldogs = append(v.imgOpBulldogs, newImageOp(img))
-	}
+	v.setSprites(render.DefaultSprites)
 
-	v.diffOpt = newOptions(v, "[D]ifficulty", engine.Difficulties, engine.DifficultyDefaultIdx)
-	v.labSizeOpt = newOptions(v, "[L]ab size", engine.LabSizes, engine.LabSizeDefaultIdx)
-	v.speedOpt = newOptions(v, "[S]peed", engine.Speeds, engine.SpeedDefaultIdx)
+	v.diffOpt = newOptions(v, screenSetup, "[D]ifficulty", engine.Difficulties, engine.DifficultyDefaultIdx)
+	v.labSizeOpt = newOptions(v, screenSetup, "[L]ab size", engine.LabSizes, engine.LabSizeDefaultIdx)
+	v.speedOpt = newOptions(v, screenSetup, "[S]peed", engine.Speeds, engine.SpeedDefaultIdx)
 
 	return v
 }
 
+// setSprites sets the sprites to draw the game with, and creates the "static" imageOps of them.
+func (v *View) setSprites(s *render.Sprites) {
+	v.sprites = s
+
+	v.imgOpMarker = newImageOp(s.Marker)
+	v.imgOpExit = newImageOp(s.Exit)
+	v.imgOpWon = newImageOp(s.Won)
+	v.spriteOps = map[*image.RGBA]imageOp{}
+
+	// Force redrawing the cached lab tiles
+	v.labTiles.tiles = nil
+}
+
+// SetThemes sets the sprite themes the user may choose from, and selects the theme at the given index.
+func (v *View) SetThemes(themes []*render.Theme, selected int) {
+	v.themeOpt = nil
+	if len(themes) > 1 {
+		v.themeOpt = newOptions(v, screenSettings, "[T]heme", themes, selected)
+	}
+	v.setSprites(themes[selected].Sprites)
+}
+
+// handleThemeInput handles user inputs that may change the theme, and applies the selected theme.
+func (v *View) handleThemeInput() {
+	if v.themeOpt == nil {
+		return
+	}
+	v.themeOpt.handleInput()
+	if s := v.themeOpt.selected().(*render.Theme).Sprites; s != v.sprites {
+		v.setSprites(s)
+	}
+}
+
 // Loop starts handing user input and frame redraws.
 // This function returns only if the user closes the app.
 func (v *View) Loop() {
@@ -144,54 +232,147 @@ func (v *View) Loop() {
 		case system.FrameEvent:
 			v.drawFrame(e)
 		case pointer.Event:
-			// TODO maybe send click on Release?
-			if e.Type == pointer.Press {
-				pos := e.Position.Sub(v.labViewOffset)
-				// apply clip
-				r := v.labViewClip
-				if pos.X >= r.Min.X && pos.X < r.Max.X &&
-					pos.Y >= r.Min.Y && pos.Y < r.Max.Y {
-					// TODO if e.Source == pointer.Touch, set left button?
-					v.engine.SendClick(engine.Click{
-						X:     int(pos.X),
-						Y:     int(pos.Y),
-						Left:  e.Buttons&pointer.ButtonLeft != 0,
-						Right: e.Buttons&pointer.ButtonRight != 0,
-					})
-				}
-			}
+			v.handlePointer(e)
 		case key.Event:
-			sendKey := func(dir engine.Dir) {
-				v.engine.SendKey(engine.Key{DirKeys: map[engine.Dir]bool{dir: true}})
-			}
-			switch e.Name {
-			case key.NameLeftArrow:
-				sendKey(engine.DirLeft)
-			case key.NameRightArrow:
-				sendKey(engine.DirRight)
-			case key.NameUpArrow:
-				sendKey(engine.DirUp)
-			case key.NameDownArrow:
-				sendKey(engine.DirDown)
-			}
-			if e.Modifiers&key.ModAlt != 0 {
-				switch e.Name {
-				case "N":
-					v.sendNewGame()
-				case "D":
-					v.diffOpt.onClick()
-				case "L":
-					v.labSizeOpt.onClick()
-				case "S":
-					v.speedOpt.onClick()
-				}
-			}
+			v.handleKey(e)
 		case system.DestroyEvent:
 			log.Println("Goodbye!")
 		}
 	}
 }
 
+// handlePointer handles a pointer event.
+// Pointer events are ignored while a screen is displayed over the lab (menu buttons handle their own events).
+func (v *View) handlePointer(e pointer.Event) {
+	if v.menu.visible() || v.controls.visible {
+		return
+	}
+
+	if v.camera.handlePointer(e) {
+		// A second finger cancels single-finger gestures
+		if e.Source == pointer.Touch {
+			v.touch.active = false
+		}
+		return
+	}
+
+	if e.Source == pointer.Touch {
+		v.handleTouch(e)
+		return
+	}
+
+	// TODO maybe send click on Release?
+	if e.Type == pointer.Press && !v.dpadClick(e.Position) && !v.minimapClick(e.Position) {
+		v.sendLabClick(e.Position, e.Buttons&pointer.ButtonLeft != 0, e.Buttons&pointer.ButtonRight != 0)
+	}
+}
+
+// handleKey handles a key event: performs the action bound to the key, if any.
+// While the controls screen is visible, key events are handled by it.
+func (v *View) handleKey(e key.Event) {
+	if v.controls.visible {
+		v.handleControlsKey(e)
+		return
+	}
+	if v.menu.visible() {
+		v.handleMenuKey(e)
+		return
+	}
+
+	action, ok := v.keys[keyCombo(e)]
+	if !ok {
+		return
+	}
+
+	switch action {
+	case config.ActionLeft:
+		v.handleDirKey(engine.DirLeft, time.Now())
+	case config.ActionRight:
+		v.handleDirKey(engine.DirRight, time.Now())
+	case config.ActionUp:
+		v.handleDirKey(engine.DirUp, time.Now())
+	case config.ActionDown:
+		v.handleDirKey(engine.DirDown, time.Now())
+	case config.ActionNewGame:
+		v.sendNewGame()
+	case config.ActionDifficulty:
+		v.diffOpt.adjust(1)
+	case config.ActionLabSize:
+		v.labSizeOpt.adjust(1)
+	case config.ActionSpeed:
+		v.speedOpt.adjust(1)
+	case config.ActionTheme:
+		if v.themeOpt != nil {
+			v.themeOpt.adjust(1)
+		}
+	case config.ActionMetrics:
+		v.showMetrics = !v.showMetrics
+	case config.ActionExportAnim:
+		v.exportAnim()
+	case config.ActionHint:
+		v.engine.RequestHint()
+	case config.ActionMinimap:
+		v.minimap.visible = !v.minimap.visible
+	case config.ActionZoomIn:
+		v.camera.zoomBy(zoomStep)
+	case config.ActionZoomOut:
+		v.camera.zoomBy(1 / zoomStep)
+	case config.ActionZoomReset:
+		v.camera.resetZoom()
+	case config.ActionPanLeft:
+		v.camera.pan(f32.Point{X: -panStepPx})
+	case config.ActionPanRight:
+		v.camera.pan(f32.Point{X: panStepPx})
+	case config.ActionPanUp:
+		v.camera.pan(f32.Point{Y: -panStepPx})
+	case config.ActionPanDown:
+		v.camera.pan(f32.Point{Y: panStepPx})
+	case config.ActionFollow:
+		v.camera.follow()
+	case config.ActionDPad:
+		v.showDPad = !v.showDPad
+	case config.ActionControls:
+		v.controls.visible, v.controls.message = true, ""
+	case config.ActionMenu:
+		v.pushScreen(screenPause)
+	case config.ActionFullscreen:
+		if v.toggleFullscreen == nil {
+			log.Println("Fullscreen mode is not supported on this platform.")
+			break
+		}
+		v.toggleFullscreen()
+	case config.ActionAutopilot:
+		v.autopilot = !v.autopilot
+		if v.autopilot {
+			if v.autopilotCtrl == nil {
+				v.autopilotCtrl = bot.NewAvoidBot()
+			}
+			v.engine.SetController(v.autopilotCtrl)
+		} else {
+			v.engine.SetController(nil)
+		}
+	}
+}
+
+// SetAutopilot sets the controller of Gopher and turns on autopilot mode.
+// The controller is also used when autopilot mode is toggled later.
+func (v *View) SetAutopilot(c engine.Controller) {
+	v.autopilot, v.autopilotCtrl = true, c
+	v.engine.SetController(c)
+}
+
+// SetKeyBindings sets the key bindings to use. Key bindings must be valid.
+func (v *View) SetKeyBindings(kb config.KeyBindings) {
+	v.keyBindings = kb.Copy()
+	v.keys = v.keyBindings.Lookup()
+}
+
+// SetKeyBindingsSaver sets the function that saves the key bindings rebound on the controls screen.
+// The view has no means to do it, as it depends on where the configuration is stored.
+func (v *View) SetKeyBindingsSaver(save func(kb config.KeyBindings) error) {
+	v.saveKeyBindings = save
+}
+
 // drawFrame draws a frame of the window.
 func (v *View) drawFrame(e system.FrameEvent) {
 	gtx := v.gtx
@@ -202,12 +383,31 @@ func (v *View) drawFrame(e system.FrameEvent) {
 	for v.newGameBtn.Clicked(v.gtx) {
 		v.sendNewGame()
 	}
+	for v.hintBtn.Clicked(v.gtx) {
+		v.engine.RequestHint()
+	}
+	for v.menuBtn.Clicked(v.gtx) {
+		if !v.menu.visible() {
+			v.pushScreen(screenPause)
+		}
+	}
+	now := time.Now()
+	v.checkLongPress(now)
+	v.checkKeyRelease(now)
 	v.diffOpt.handleInput()
 	v.labSizeOpt.handleInput()
 	v.speedOpt.handleInput()
+	v.handleThemeInput()
 
 	v.drawControls()
 	v.drawLab()
+	v.drawMinimap()
+	v.drawDPad()
+	v.drawMetrics()
+	v.checkHighScore()
+	v.drawHUD()
+	v.drawMenu()
+	v.drawControlsScreen()
 
 	e.Frame(gtx.Ops)
 }
@@ -218,30 +418,69 @@ func (v *View) sendNewGame() {
 		Difficulty: v.diffOpt.selected().(*engine.Difficulty),
 		LabSize:    v.labSizeOpt.selected().(*engine.LabSize),
 		Speed:      v.speedOpt.selected().(*engine.Speed),
+		Gen:        v.genOpts,
 	})
 }
 
+// SetDPad sets the visibility of the on-screen D-pad.
+func (v *View) SetDPad(visible bool) {
+	v.showDPad = visible
+}
+
+// SetFullscreenToggle sets the function that toggles fullscreen mode of the window.
+// The view has no means to do it, as it depends on the platform.
+func (v *View) SetFullscreenToggle(toggle func()) {
+	v.toggleFullscreen = toggle
+}
+
+// SetGenOptions sets the labyrinth generation options to use for new games.
+func (v *View) SetGenOptions(opts engine.GenOptions) {
+	v.genOpts = opts
+}
+
 // drawControls draws the control and setup widgets.
 func (v *View) drawControls() {
 	th, gtx := v.th, v.gtx
 
-	layout.N.Layout(gtx, func() {
-		layout.UniformInset(unit.Px(5)).Layout(gtx, func() {
-			layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
-				layout.Rigid(func() {
-					layout.Inset{Left: unit.Px(10), Right: unit.Px(10)}.Layout(gtx, func() {
-						b := th.Button("[N]ew Game")
-						b.Background = color.RGBA{R: 20, G: 130, B: 20, A: 255}
-						b.Layout(gtx, v.newGameBtn)
-					})
-				}),
-				layout.Rigid(v.diffOpt.layout),
-				layout.Rigid(v.labSizeOpt.layout),
-				layout.Rigid(v.speedOpt.layout),
-			)
+	children := []layout.Widget{
+		func() {
+			layout.Inset{Left: unit.Px(10)}.Layout(gtx, func() {
+				b := th.Button("Menu")
+				b.Background = color.RGBA{R: 60, G: 60, B: 90, A: 255}
+				b.Layout(gtx, v.menuBtn)
+			})
+		},
+		func() {
+			layout.Inset{Left: unit.Px(10), Right: unit.Px(10)}.Layout(gtx, func() {
+				b := th.Button("[N]ew Game")
+				b.Background = color.RGBA{R: 20, G: 130, B: 20, A: 255}
+				b.Layout(gtx, v.newGameBtn)
+			})
+		},
+		v.diffOpt.layout,
+		v.labSizeOpt.layout,
+		v.speedOpt.layout,
+	}
+	if v.themeOpt != nil {
+		children = append(children, v.themeOpt.layout)
+	}
+	children = append(children, func() {
+		layout.Inset{Left: unit.Px(10), Right: unit.Px(10)}.Layout(gtx, func() {
+			m := v.engine.Model
+			m.RLock()
+			hintsLeft := m.HintsLeft
+			m.RUnlock()
+			b := th.Button(fmt.Sprintf("[H]int (%d)", hintsLeft))
+			b.Background = color.RGBA{R: 20, G: 20, B: 130, A: 255}
+			b.Layout(gtx, v.hintBtn)
 		})
-		v.controlsHeightPx = gtx.Dimensions.Size.Y
 	})
+
+	// Controls are wrapped into multiple rows if the window is narrow.
+	layout.UniformInset(unit.Px(5)).Layout(gtx, func() {
+		flow(gtx, children...)
+	})
+	v.controlsHeightPx = gtx.Dimensions.Size.Y
 }
 
 // drawLab draws the labyrinth.
@@ -256,9 +495,10 @@ func (v *View) drawLab() {
 	// after transformations undone.
 	defer func() {
 		if m.Won {
+			r := v.labViewRect()
 			v.drawImg(v.imgOpWon,
-				v.labViewOffset.X+v.labViewClip.Min.X+(v.labViewClip.Dx()-float32(v.imgOpWon.src.Bounds().Dx()))/2,
-				v.labViewOffset.Y+v.labViewClip.Min.Y+(v.labViewClip.Dy()-float32(v.imgOpWon.src.Bounds().Dy()))/2,
+				r.Min.X+(r.Dx()-float32(v.imgOpWon.src.Bounds().Dx()))/2,
+				r.Min.Y+(r.Dy()-float32(v.imgOpWon.src.Bounds().Dy()))/2,
 			)
 		}
 	}()
@@ -267,25 +507,28 @@ func (v *View) drawLab() {
 	stack.Push(gtx.Ops)
 	defer stack.Pop()
 
-	// Center lab view in window:
-	displayWidth, displayHeight := float32(viewWidthPx), float32(viewHeightPx)
+	zoom, center := v.camera.update(m.Counter, f32.Point{X: float32(m.Gopher.Pos.X), Y: float32(m.Gopher.Pos.Y)}, time.Now())
+
+	// The lab view fills the window below the controls, center it in the window:
+	displayWidth := float32(gtx.Constraints.Width.Max)
+	displayHeight := float32(gtx.Constraints.Height.Max - v.controlsHeightPx)
+	if displayHeight < 1 {
+		displayHeight = 1
+	}
 	labWidth := float32(m.Cols * engine.BlockSize)
 	labHeight := float32(m.Rows * engine.BlockSize)
-	if labWidth < displayWidth {
-		displayWidth = labWidth
+	if labWidth*zoom < displayWidth {
+		displayWidth = labWidth * zoom
 	}
-	if labHeight < displayHeight {
-		displayHeight = labHeight
+	if labHeight*zoom < displayHeight {
+		displayHeight = labHeight * zoom
 	}
 
-	// Calculate the visible window of the lab image.
-	// Try to center Gopher in view:
+	// Calculate the visible window of the lab image (in lab coordinates).
+	// Try to center the camera's center in view:
 	rect := f32.Rectangle{}
-	rect.Max = f32.Point{X: displayWidth, Y: displayHeight}
-	rect = rect.Add(f32.Point{
-		X: float32(m.Gopher.Pos.X) - displayWidth/2,
-		Y: float32(m.Gopher.Pos.Y) - displayHeight/2,
-	})
+	rect.Max = f32.Point{X: displayWidth / zoom, Y: displayHeight / zoom}
+	rect = rect.Add(center.Sub(rect.Max.Mul(0.5)))
 	// But needs correction at the edges of the view (it can't be centered)
 	corr := f32.Point{}
 	if rect.Min.X < 0 {
@@ -301,44 +544,106 @@ func (v *View) drawLab() {
 		corr.Y = labHeight - rect.Max.Y
 	}
 	rect = rect.Add(corr)
+	if v.camera.free {
+		// Don't let the camera leave the lab
+		v.camera.center = rect.Min.Add(rect.Max).Mul(0.5)
+	}
 
-	v.labViewOffset = f32.Point{
+	v.labViewOrigin = f32.Point{
 		X: (float32(gtx.Constraints.Width.Max) - displayWidth) / 2,
 		Y: float32(v.controlsHeightPx),
-	}.Sub(rect.Min)
-	op.TransformOp{}.Offset(v.labViewOffset).Add(gtx.Ops)
+	}
 	v.labViewClip = rect
-	clip.Rect{Rect: v.labViewClip}.Op(gtx.Ops).Add(gtx.Ops)
+	v.labViewZoom = zoom
+	op.TransformOp{}.Offset(v.labViewOrigin).Add(gtx.Ops)
+	clip.Rect{Rect: f32.Rectangle{Max: f32.Point{X: displayWidth, Y: displayHeight}}}.Op(gtx.Ops).Add(gtx.Ops)
 
 	// First the blocks:
-	v.ensureLabImgOp()
-	v.drawImg(v.labImgOp, 0, 0)
+	v.drawLabTiles(m)
+
+	// Hint path:
+	if m.Hint != nil {
+		paint.ColorOp{Color: render.SolutionColor}.Add(gtx.Ops)
+		for _, p := range m.Hint {
+			r := f32.Rectangle{Min: f32.Point{X: float32(p.X * engine.BlockSize), Y: float32(p.Y * engine.BlockSize)}}
+			r.Max = r.Min.Add(f32.Point{X: engine.BlockSize, Y: engine.BlockSize})
+			if r.Intersect(v.labViewClip).Empty() {
+				continue
+			}
+			paint.PaintOp{Rect: v.labToView(r)}.Add(gtx.Ops)
+		}
+	}
 
-	// Now objects in the lab:
-	// TODO do not draw images outside of the view
+	// Now objects in the lab (drawLabImg skips the ones outside of the view):
 
 	// Draw target position markers:
-	mbounds := imgMarker.Bounds()
+	mbounds := v.sprites.Marker.Bounds()
 	tp := m.Gopher.TargetPos
-	v.drawImg(v.imgOpMarker, float32(tp.X-mbounds.Dx()/2), float32(tp.Y-mbounds.Dy()/2))
+	v.drawLabImg(v.imgOpMarker, float32(tp.X-mbounds.Dx()/2), float32(tp.Y-mbounds.Dy()/2))
 	for _, tp := range m.TargetPoss {
-		v.drawImg(v.imgOpMarker, float32(tp.X-mbounds.Dx()/2), float32(tp.Y-mbounds.Dy()/2))
+		v.drawLabImg(v.imgOpMarker, float32(tp.X-mbounds.Dx()/2), float32(tp.Y-mbounds.Dy()/2))
 	}
 	// Gopher:
-	if m.Dead {
-		v.drawObj(v.imgOpDead, m.Gopher)
-	} else {
-		v.drawObj(v.imgOpGophers[m.Gopher.Dir], m.Gopher)
+	var over time.Duration
+	if m.Dead || m.Won {
+		if v.overCounter != m.Counter {
+			v.overCounter, v.overSince = m.Counter, time.Now()
+		}
+		over = time.Since(v.overSince)
 	}
+	v.drawObj(v.spriteOp(v.sprites.GopherImage(m, over)), m.Gopher)
 	// Bulldogs:
 	for _, bd := range m.Bulldogs {
-		v.drawObj(v.imgOpBulldogs[bd.Dir], bd)
+		v.drawObj(v.spriteOp(v.sprites.BulldogImage(bd)), bd)
 	}
 }
 
+// spriteOp returns the imageOp of the given sprite image, created on first use.
+func (v *View) spriteOp(img *image.RGBA) imageOp {
+	iop, ok := v.spriteOps[img]
+	if !ok {
+		iop = newImageOp(img)
+		v.spriteOps[img] = iop
+	}
+	return iop
+}
+
 // drawObj draws the given image of the given moving obj.
 func (v *View) drawObj(iop imageOp, obj *engine.MovingObj) {
-	v.drawImg(iop, float32(obj.Pos.X-engine.BlockSize/2), float32(obj.Pos.Y-engine.BlockSize/2))
+	v.drawLabImg(iop, float32(obj.Pos.X-engine.BlockSize/2), float32(obj.Pos.Y-engine.BlockSize/2))
+}
+
+// drawLabImg draws the given image to the given position in lab coordinates,
+// applying the zoom of the lab view. Images outside of the view are not drawn.
+// The transformation to the lab view origin must be applied.
+func (v *View) drawLabImg(iop imageOp, x, y float32) {
+	b := iop.src.Bounds()
+	r := f32.Rectangle{Min: f32.Point{X: x, Y: y}}
+	r.Max = r.Min.Add(f32.Point{X: float32(b.Dx()), Y: float32(b.Dy())})
+	if r.Intersect(v.labViewClip).Empty() {
+		return
+	}
+
+	iop.Add(v.gtx.Ops)
+	paint.PaintOp{Rect: v.labToView(r)}.Add(v.gtx.Ops)
+}
+
+// labToView converts the given rectangle in lab coordinates to the lab view's coordinates
+// (relative to the lab view origin).
+func (v *View) labToView(r f32.Rectangle) f32.Rectangle {
+	r = r.Sub(v.labViewClip.Min)
+	return f32.Rectangle{Min: r.Min.Mul(v.labViewZoom), Max: r.Max.Mul(v.labViewZoom)}
+}
+
+// toLab converts the given point in window coordinates to lab coordinates.
+func (v *View) toLab(p f32.Point) f32.Point {
+	return p.Sub(v.labViewOrigin).Mul(1 / v.labViewZoom).Add(v.labViewClip.Min)
+}
+
+// labViewRect returns the rectangle of the lab view in window coordinates where it was last drawn.
+func (v *View) labViewRect() f32.Rectangle {
+	size := v.labViewClip.Size().Mul(v.labViewZoom)
+	return f32.Rectangle{Min: v.labViewOrigin, Max: v.labViewOrigin.Add(size)}
 }
 
 // drawImg draws the given image to the given position.
@@ -356,35 +661,3 @@ func (v *View) drawImg(iop imageOp, x, y float32) {
 
 	stack.Pop()
 }
-
-// ensureLabImgOp ensures labImgOp is up-to-date
-func (v *View) ensureLabImgOp() {
-	m := v.engine.Model
-	if v.gameCounter == m.Counter {
-		// We have the lab image of the current game
-		return
-	}
-
-	labImg := image.NewRGBA(image.Rect(0, 0, m.Cols*engine.BlockSize, m.Rows*engine.BlockSize))
-	var r image.Rectangle
-	for row := range m.Lab {
-		r.Min.Y = row * engine.BlockSize
-		r.Max.Y = r.Min.Y + engine.BlockSize
-		for col, block := range m.Lab[row] {
-			r.Min.X = col * engine.BlockSize
-			r.Max.X = r.Min.X + engine.BlockSize
-			src := imgBlocks[block]
-			draw.Draw(labImg, r, src, image.Point{}, draw.Over)
-		}
-	}
-
-	// Exit sign:
-	r.Min = m.ExitPos
-	r.Min = r.Min.Add(image.Point{-engine.BlockSize / 2, -engine.BlockSize / 2})
-	r.Max = r.Min.Add(image.Point{engine.BlockSize, engine.BlockSize})
-	draw.Draw(labImg, r, imgExit, image.Point{}, draw.Over)
-
-	v.labImgOp = newImageOp(labImg)
-
-	v.gameCounter = m.Counter
-}